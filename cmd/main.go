@@ -111,7 +111,29 @@ func main() {
 	rateCalc := collector.NewRateCalculator()
 	var workers []*collector.Worker
 	for _, mgr := range managers {
-		w := collector.NewWorker(mgr, writer, cfg.Intervals, cfg.InterfaceSpeeds, rateCalc, alertEval, nil)
+		w, err := collector.NewWorker(mgr, writer, cfg.Intervals, cfg.InterfaceSpeeds, rateCalc, alertEval, nil)
+		if err != nil {
+			logger.Fatal("building nsx client failed", zap.String("site", mgr.Site), zap.Error(err))
+		}
+
+		// Verify the Manager certificate once at startup: a CA/pin problem
+		// will not fix itself, so fail loudly instead of logging an error
+		// every cycle. An unreachable Manager is only a warning.
+		tlsCtx, tlsCancel := context.WithTimeout(context.Background(), 15*time.Second)
+		if err := w.Client().CheckTLS(tlsCtx); nsx.IsTLSVerify(err) {
+			logger.Fatal("nsx manager certificate rejected", zap.String("site", mgr.Site), zap.Error(err))
+		} else if err != nil {
+			logger.Warn("nsx manager tls check failed", zap.String("site", mgr.Site), zap.Error(err))
+		}
+		// Read the Manager version up front so the capability registry is
+		// populated before the first cycle; the slow path keeps it fresh.
+		version := "unknown"
+		if nv, err := w.Client().RefreshVersion(tlsCtx); err != nil {
+			logger.Warn("nsx manager version check failed", zap.String("site", mgr.Site), zap.Error(err))
+		} else {
+			version = nv.ProductVersion
+		}
+		tlsCancel()
 
 		// Build the t1watch.Notifier (Slack). Channel resolution:
 		//   1. t1_watch.slack_channel (preferred — segregates capacity events)
@@ -149,10 +171,72 @@ func main() {
 		)
 		w.SetCapacityCollector(capCollector)
 
+		if cfg.T1Traffic.Enabled {
+			w.SetT1TrafficCollector(collector.NewT1TrafficCollector(
+				mgr.Site, w.Client(), rateCalc, cfg.T1Traffic,
+				logger.Named(mgr.Site).Named("t1_traffic"),
+			))
+		}
+
+		if cfg.Tunnels.Enabled {
+			w.SetTunnelCollector(collector.NewTunnelCollector(
+				mgr.Site, w.Client(), cfg.Tunnels,
+				logger.Named(mgr.Site).Named("tunnels"),
+			))
+		}
+
+		if cfg.Realization.Enabled {
+			w.SetRealizationCollector(collector.NewRealizationCollector(
+				mgr.Site, w.Client(), cfg.Realization,
+				logger.Named(mgr.Site).Named("realization"),
+			))
+		}
+
+		if cfg.FWRuleStats.Enabled {
+			w.SetFWRuleStatsCollector(collector.NewFWRuleStatsCollector(
+				mgr.Site, w.Client(), cfg.T1Watch.StateDir, cfg.FWRuleStats,
+				logger.Named(mgr.Site).Named("fw_rule_stats"),
+			))
+		}
+
+		if cfg.Certificates.Enabled == nil || *cfg.Certificates.Enabled {
+			var certSlack t1watch.SlackPoster
+			certChannel := cfg.Certificates.SlackChannel
+			if certChannel == "" {
+				certChannel = cfg.Slack.Channel
+			}
+			if slackToken != "" && certChannel != "" && len(cfg.Certificates.NotifyDays) > 0 {
+				certSlack = alerting.NewSlackClient(slackToken, certChannel)
+			}
+			w.SetCertificateCollector(collector.NewCertificateCollector(
+				mgr.Site, w.Client(), cfg.T1Watch.StateDir,
+				cfg.Certificates.NotifyDays, certSlack,
+				logger.Named(mgr.Site).Named("certificates"),
+			))
+		}
+
+		if cfg.Backup.Enabled == nil || *cfg.Backup.Enabled {
+			var backupSlack t1watch.SlackPoster
+			backupChannel := cfg.Backup.SlackChannel
+			if backupChannel == "" {
+				backupChannel = cfg.Slack.Channel
+			}
+			if slackToken != "" && backupChannel != "" {
+				backupSlack = alerting.NewSlackClient(slackToken, backupChannel)
+			}
+			w.SetBackupCollector(collector.NewBackupCollector(
+				mgr.Site, w.Client(), cfg.T1Watch.StateDir,
+				cfg.Backup.MaxAge, backupSlack,
+				logger.Named(mgr.Site).Named("backup"),
+			))
+		}
+
 		workers = append(workers, w)
 		logger.Info("manager registered",
 			zap.String("site", mgr.Site),
 			zap.String("url", mgr.URL),
+			zap.String("auth_mode", mgr.AuthMode),
+			zap.String("nsx_version", version),
 		)
 	}
 
@@ -199,6 +283,14 @@ func main() {
 		logger.Fatal("scheduler error", zap.Error(err))
 	}
 
+	// Log out of every Manager session before exiting. The run context is
+	// already cancelled at this point, so use a short dedicated timeout.
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	for _, w := range workers {
+		w.Close(closeCtx)
+	}
+	closeCancel()
+
 	logger.Info("nsx-collector stopped")
 }
 
@@ -218,8 +310,14 @@ func runPrintClusters(managers []config.Manager) {
 	var out []collector.T0Cluster
 	failed := false
 	for _, mgr := range managers {
-		client := nsx.NewClient(mgr.URL, mgr.Username, mgr.Password, mgr.TLSSkipVerify)
+		client, err := collector.NewClient(mgr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "print-clusters: %s: %v\n", mgr.Site, err)
+			failed = true
+			continue
+		}
 		clusters, err := collector.ListT0Clusters(ctx, client, mgr.Site)
+		client.Close(ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "print-clusters: %s: %v\n", mgr.Site, err)
 			failed = true
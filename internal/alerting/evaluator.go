@@ -60,12 +60,20 @@ func NewEvaluator(slack *SlackClient, grafana *GrafanaConfig, reader utilReader,
 	}
 }
 
+// ErrorRates are the per-second error and drop rates of the alerted
+// interface, computed by the collector between two polls.
+type ErrorRates struct {
+	RxErrorsPerSec, TxErrorsPerSec float64
+	RxDropsPerSec, TxDropsPerSec   float64
+	RxDropPct, TxDropPct           float64 // drops / (delivered + drops)
+}
+
 // Evaluate checks utilization and sends alerts.
 // Instead of using the instantaneous rate just computed by the collector,
 // it queries InfluxDB with the same aggregation Grafana uses, so the alert
 // fires on the exact value the dashboard displays.
-// rxErrors/txErrors are cumulative error rates (errors/sec) from the collector.
-func (e *Evaluator) Evaluate(site, nodeName, ifaceID string, rxUtilPct, txUtilPct float64, linkSpeedMbps int64, rxBps, txBps float64, rxErrors, txErrors int64) {
+// errs carries the interface's error/drop rates for the message text.
+func (e *Evaluator) Evaluate(site, nodeName, ifaceID string, rxUtilPct, txUtilPct float64, linkSpeedMbps int64, rxBps, txBps float64, errs ErrorRates) {
 	if e.reader == nil {
 		// Without a reader we refuse to alert on raw/instantaneous samples;
 		// that mode caused all the false positives this package used to have.
@@ -118,7 +126,7 @@ func (e *Evaluator) Evaluate(site, nodeName, ifaceID string, rxUtilPct, txUtilPc
 	e.cooldown[key] = now
 	e.mu.Unlock()
 
-	msg := e.formatAlert(site, nodeName, ifaceID, direction, bps, maxUtil, linkSpeedMbps, rxErrors, txErrors)
+	msg := e.formatAlert(site, nodeName, ifaceID, direction, bps, maxUtil, linkSpeedMbps, errs)
 	ts, err := e.slack.Post(msg)
 	if err != nil {
 		e.logger.Error("slack alert failed", zap.Error(err))
@@ -143,12 +151,18 @@ func (e *Evaluator) canAlert(key string, now time.Time) bool {
 	return now.Sub(last) >= e.cooldownDuration
 }
 
-func (e *Evaluator) formatAlert(site, nodeName, ifaceID, direction string, bps, utilPct float64, linkSpeedMbps int64, rxErrors, txErrors int64) string {
+func (e *Evaluator) formatAlert(site, nodeName, ifaceID, direction string, bps, utilPct float64, linkSpeedMbps int64, errs ErrorRates) string {
 	dashLink := e.dashboardLink(nodeName)
 
 	errMsg := "Nenhum"
-	if rxErrors > 0 || txErrors > 0 {
-		errMsg = fmt.Sprintf("RX: %d/s | TX: %d/s", rxErrors, txErrors)
+	if errs.RxErrorsPerSec > 0 || errs.TxErrorsPerSec > 0 {
+		errMsg = fmt.Sprintf("RX: %s/s | TX: %s/s", formatRate(errs.RxErrorsPerSec), formatRate(errs.TxErrorsPerSec))
+	}
+	dropMsg := "Nenhum"
+	if errs.RxDropsPerSec > 0 || errs.TxDropsPerSec > 0 {
+		dropMsg = fmt.Sprintf("RX: %s/s (%.2f%%) | TX: %s/s (%.2f%%)",
+			formatRate(errs.RxDropsPerSec), errs.RxDropPct,
+			formatRate(errs.TxDropsPerSec), errs.TxDropPct)
 	}
 
 	icon := ":warning:"
@@ -165,7 +179,8 @@ func (e *Evaluator) formatAlert(site, nodeName, ifaceID, direction string, bps,
 			"*Site:* %s\n\n"+
 			"*%s:* %s / %d Gbps (*%.1f%%*)\n"+
 			"*Link Speed:* %d Mbps\n"+
-			"*Erros:* %s\n\n"+
+			"*Erros:* %s\n"+
+			"*Descartes:* %s\n\n"+
 			":chart_with_upwards_trend: <%s|Ver no Grafana>",
 		icon, level,
 		time.Now().Format("02/01/2006 15:04:05"),
@@ -175,6 +190,7 @@ func (e *Evaluator) formatAlert(site, nodeName, ifaceID, direction string, bps,
 		direction, formatBps(bps), linkSpeedMbps/1000, utilPct,
 		linkSpeedMbps,
 		errMsg,
+		dropMsg,
 		dashLink,
 	)
 }
@@ -253,6 +269,15 @@ func (e *Evaluator) attachScreenshot(site, nodeName, direction, threadTS string)
 	}
 }
 
+// formatRate prints a per-second rate with one decimal below 10 and none
+// above (0.3/s, 12/s, 1530/s).
+func formatRate(r float64) string {
+	if r < 10 {
+		return fmt.Sprintf("%.1f", r)
+	}
+	return fmt.Sprintf("%.0f", r)
+}
+
 func formatBps(bps float64) string {
 	switch {
 	case bps >= 1e9:
@@ -0,0 +1,233 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/t1watch"
+	"nsx-collector/internal/telemetry"
+)
+
+// BackupCollector reads the Manager backup configuration and history on the
+// slow path and writes one nsx_backup point per backup type. When the last
+// successful cluster backup is older than backup.max_age (or there is none)
+// it posts a Slack notice, once per transition; another notice follows when
+// backups are fresh again. The notified state is persisted in
+// <state_dir>/backup-<site>.json so a restart does not repeat the notice.
+type BackupCollector struct {
+	site     string
+	client   *nsx.Client
+	logger   *zap.Logger
+	stateDir string
+	maxAge   time.Duration
+	// slack is nil when notices are off (no token/channel).
+	slack t1watch.SlackPoster
+
+	mu sync.Mutex
+	// stale is the state last notified; evaluated is false until the first
+	// successful read (or a state file was loaded).
+	stale     bool
+	evaluated bool
+}
+
+// backupState is the notified stale flag of one site, kept across restarts.
+type backupState struct {
+	Site    string    `json:"site"`
+	Updated time.Time `json:"updated"`
+	Stale   bool      `json:"stale"`
+}
+
+// NewBackupCollector creates the backup collector for one manager.
+// slack may be nil: points are still written, only notices are skipped.
+func NewBackupCollector(site string, client *nsx.Client, stateDir string, maxAge time.Duration, slack t1watch.SlackPoster, logger *zap.Logger) *BackupCollector {
+	return &BackupCollector{
+		site:     site,
+		client:   client,
+		logger:   logger,
+		stateDir: stateDir,
+		maxAge:   maxAge,
+		slack:    slack,
+	}
+}
+
+// Collect reads the backup overview and history and returns the
+// nsx_backup points, posting the stale/fresh notice when it changes.
+func (b *BackupCollector) Collect(ctx context.Context, now time.Time) ([]*write.Point, error) {
+	site := b.site
+	ov, err := b.client.GetBackupOverview(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hist, err := b.client.GetBackupHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enabled := ov.BackupConfig.BackupEnabled
+
+	var points []*write.Point
+	var clusterSuccess, clusterFailure *nsx.BackupStatus
+	for _, bt := range []struct {
+		name     string
+		statuses []nsx.BackupStatus
+	}{
+		{"cluster", hist.ClusterBackupStatuses},
+		{"node", hist.NodeBackupStatuses},
+		{"inventory", hist.InventoryBackupStatuses},
+	} {
+		if bt.name != "cluster" && len(bt.statuses) == 0 {
+			continue
+		}
+		success, failure := nsx.LatestBackups(bt.statuses)
+		var failures int64
+		for i := range bt.statuses {
+			if !bt.statuses[i].Success {
+				failures++
+			}
+		}
+		var lastSuccess, lastFailure time.Time
+		ageHours := -1.0
+		if success != nil {
+			lastSuccess = success.End()
+			ageHours = now.Sub(lastSuccess).Hours()
+		}
+		lastOK := success != nil
+		lastError := ""
+		if failure != nil {
+			lastFailure = failure.End()
+			if lastFailure.After(lastSuccess) {
+				lastOK = false
+				lastError = failure.ErrorCode
+				if lastError == "" {
+					lastError = "unknown"
+				}
+			}
+		}
+		points = append(points, influxpkg.BackupPoint(
+			site, bt.name, lastError, enabled, lastOK,
+			lastSuccess, lastFailure, ageHours,
+			int64(len(bt.statuses)), failures, now,
+		))
+		if bt.name == "cluster" {
+			clusterSuccess, clusterFailure = success, failure
+		}
+	}
+
+	stale := clusterSuccess == nil || now.Sub(clusterSuccess.End()) > b.maxAge
+	b.notify(stale, enabled, clusterSuccess, clusterFailure, now)
+
+	b.logger.Debug("backups collected",
+		zap.Bool("enabled", enabled),
+		zap.Bool("stale", stale),
+		zap.Int("cluster_attempts", len(hist.ClusterBackupStatuses)),
+	)
+	return points, nil
+}
+
+// notify posts a Slack notice when the stale state changes (or on the first
+// evaluation, if already stale).
+func (b *BackupCollector) notify(stale, enabled bool, success, failure *nsx.BackupStatus, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.evaluated {
+		b.loadState()
+	}
+	changed := stale != b.stale || (!b.evaluated && stale)
+	b.evaluated = true
+	if !changed {
+		return
+	}
+	if stale {
+		b.logger.Warn("nsx backup stale", zap.Bool("enabled", enabled), zap.Duration("max_age", b.maxAge))
+	} else {
+		b.logger.Info("nsx backup fresh again")
+	}
+	if b.slack == nil {
+		b.setStale(stale)
+		return
+	}
+	msg := formatBackupNotice(b.site, stale, enabled, success, failure, b.maxAge, now)
+	if _, err := b.slack.Post(msg); err != nil {
+		// Keep the old state so the next cycle retries the notice.
+		b.logger.Warn("backup: slack notice failed", zap.Error(err))
+		return
+	}
+	telemetry.BackupNotices.WithLabelValues(b.site).Inc()
+	b.setStale(stale)
+}
+
+// setStale records the notified state and persists it. b.mu must be held.
+func (b *BackupCollector) setStale(stale bool) {
+	b.stale = stale
+	data, err := json.MarshalIndent(&backupState{
+		Site:    b.site,
+		Updated: time.Unix(time.Now().Unix(), 0).UTC(),
+		Stale:   stale,
+	}, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(b.statePath(), data)
+	}
+	if err != nil {
+		b.logger.Warn("backup: save state failed, the notice may repeat after a restart", zap.Error(err))
+	}
+}
+
+// loadState restores the notified state saved by a previous run, if any.
+// b.mu must be held.
+func (b *BackupCollector) loadState() {
+	data, err := os.ReadFile(b.statePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			b.logger.Warn("backup: load state failed, the notice may repeat", zap.Error(err))
+		}
+		return
+	}
+	var st backupState
+	if err := json.Unmarshal(data, &st); err != nil {
+		b.logger.Warn("backup: load state failed, the notice may repeat", zap.Error(err))
+		return
+	}
+	b.stale = st.Stale
+	b.evaluated = true
+}
+
+func (b *BackupCollector) statePath() string {
+	safeSite := strings.ToLower(strings.ReplaceAll(b.site, "/", "_"))
+	return filepath.Join(b.stateDir, "backup-"+safeSite+".json")
+}
+
+// formatBackupNotice builds the Slack text for a stale or recovered backup.
+func formatBackupNotice(site string, stale, enabled bool, success, failure *nsx.BackupStatus, maxAge time.Duration, now time.Time) string {
+	const layout = "2006-01-02 15:04 MST"
+	if !stale {
+		return fmt.Sprintf("Backup do NSX Manager no %s normalizado: ultimo sucesso em %s (ha %.0fh).",
+			site, success.End().UTC().Format(layout), now.Sub(success.End()).Hours())
+	}
+	var msg string
+	if success == nil {
+		msg = fmt.Sprintf("Backup do NSX Manager no %s sem nenhum sucesso no historico", site)
+	} else {
+		msg = fmt.Sprintf("Ultimo backup com sucesso do NSX Manager no %s foi ha %.0fh (%s), acima do limite de %.0fh",
+			site, now.Sub(success.End()).Hours(), success.End().UTC().Format(layout), maxAge.Hours())
+	}
+	if !enabled {
+		msg += " — backup agendado DESABILITADO"
+	}
+	if failure != nil && (success == nil || failure.End().After(success.End())) {
+		msg += fmt.Sprintf(". Ultima falha em %s", failure.End().UTC().Format(layout))
+		if failure.ErrorCode != "" || failure.ErrorMessage != "" {
+			msg += fmt.Sprintf(" (%s: %s)", failure.ErrorCode, failure.ErrorMessage)
+		}
+	}
+	return msg + "."
+}
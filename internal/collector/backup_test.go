@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/nsx"
+)
+
+type fakeSlack struct {
+	posts []string
+	err   error
+}
+
+func (f *fakeSlack) Post(text string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.posts = append(f.posts, text)
+	return "ts", nil
+}
+
+func TestBackupNoticeTransitions(t *testing.T) {
+	slack := &fakeSlack{}
+	b := NewBackupCollector(testSite, nil, t.TempDir(), 25*time.Hour, slack, zap.NewNop())
+	now := time.Unix(1_700_000_000, 0)
+	old := &nsx.BackupStatus{EndTime: now.Add(-30 * time.Hour).UnixMilli(), Success: true}
+	fresh := &nsx.BackupStatus{EndTime: now.Add(-2 * time.Hour).UnixMilli(), Success: true}
+
+	b.notify(false, true, fresh, nil, now)
+	if len(slack.posts) != 0 {
+		t.Fatalf("fresh baseline posted %v", slack.posts)
+	}
+	b.notify(true, true, old, nil, now)
+	b.notify(true, true, old, nil, now)
+	if len(slack.posts) != 1 {
+		t.Fatalf("stale posts = %d, want 1", len(slack.posts))
+	}
+
+	// A failed post is retried on the next cycle.
+	slack.err = errors.New("slack down")
+	b.notify(false, true, fresh, nil, now)
+	slack.err = nil
+	b.notify(false, true, fresh, nil, now)
+	if len(slack.posts) != 2 {
+		t.Fatalf("posts after recovery = %d, want 2", len(slack.posts))
+	}
+
+	// Stale on the very first evaluation is notified.
+	slack2 := &fakeSlack{}
+	b2 := NewBackupCollector(testSite, nil, t.TempDir(), 25*time.Hour, slack2, zap.NewNop())
+	b2.notify(true, false, nil, nil, now)
+	if len(slack2.posts) != 1 {
+		t.Fatalf("first stale posts = %d, want 1", len(slack2.posts))
+	}
+}
+
+func TestBackupNoticeSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1_700_000_000, 0)
+	old := &nsx.BackupStatus{EndTime: now.Add(-30 * time.Hour).UnixMilli(), Success: true}
+	fresh := &nsx.BackupStatus{EndTime: now.Add(-2 * time.Hour).UnixMilli(), Success: true}
+
+	slack := &fakeSlack{}
+	NewBackupCollector(testSite, nil, dir, 25*time.Hour, slack, zap.NewNop()).notify(true, true, old, nil, now)
+	if len(slack.posts) != 1 {
+		t.Fatalf("stale posts = %d, want 1", len(slack.posts))
+	}
+
+	// Restarted while still stale: no second notice, but recovery is posted.
+	slack = &fakeSlack{}
+	b := NewBackupCollector(testSite, nil, dir, 25*time.Hour, slack, zap.NewNop())
+	b.notify(true, true, old, nil, now)
+	if len(slack.posts) != 0 {
+		t.Fatalf("stale notice repeated after restart: %v", slack.posts)
+	}
+	b.notify(false, true, fresh, nil, now)
+	if len(slack.posts) != 1 {
+		t.Fatalf("recovery posts = %d, want 1", len(slack.posts))
+	}
+}
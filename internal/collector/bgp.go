@@ -0,0 +1,268 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// localeServicesTTL is how long the locale-services paths of a T0/VRF are
+// reused before being listed again. They almost never change, and skipping
+// the listing halves the requests per BGP poll.
+const localeServicesTTL = 10 * time.Minute
+
+// bgpSession is the last observed state of one session (neighbor × edge).
+type bgpSession struct {
+	state       string
+	established bool
+}
+
+// BGPCollector reads the BGP neighbor status of every T0 and VRF, per edge
+// node, and emits down/up events when a session changes state between two
+// polls. Previous states live in memory only — the first cycle after a
+// restart baselines without events (same as HACollector).
+type BGPCollector struct {
+	site   string
+	client *nsx.Client
+	logger *zap.Logger
+
+	mu sync.Mutex
+	// prev[t0Path][edgeID|neighbor] = last state seen.
+	prev map[string]map[string]bgpSession
+	// ls[t0Path] = locale-services paths, refreshed every localeServicesTTL.
+	ls        map[string][]string
+	lsFetched map[string]time.Time
+}
+
+// NewBGPCollector creates the BGP collector for one manager.
+func NewBGPCollector(site string, client *nsx.Client, logger *zap.Logger) *BGPCollector {
+	return &BGPCollector{
+		site:      site,
+		client:    client,
+		logger:    logger,
+		prev:      map[string]map[string]bgpSession{},
+		ls:        map[string][]string{},
+		lsFetched: map[string]time.Time{},
+	}
+}
+
+// CollectBGP runs one BGP poll:
+//  1. Lists T0s/VRFs (Policy API, shared inventory cache).
+//  2. Per gateway, resolves locale-services and reads bgp/neighbors/status
+//     (parallelism bounded by the client's in-flight cap).
+//  3. Returns one nsx_bgp_neighbor point per session plus nsx_bgp_event
+//     points for sessions that went down or came back up.
+func (b *BGPCollector) CollectBGP(ctx context.Context) ([]*write.Point, error) {
+	site := b.site
+	telemetry.BGPPolls.WithLabelValues(site).Inc()
+
+	t0s, err := b.client.GetPolicyTier0s(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bgp: list tier-0s: %w", err)
+	}
+
+	// Edge display names for sessions whose status lacks target_display_name.
+	tnNames := map[string]string{}
+	if tns, err := b.client.GetTransportNodes(ctx); err == nil {
+		for _, tn := range tns {
+			if tn.ID != "" && tn.DisplayName != "" {
+				tnNames[tn.ID] = tn.DisplayName
+			}
+		}
+	} else {
+		b.logger.Debug("bgp: GetTransportNodes failed, names unavailable", zap.Error(err))
+	}
+
+	now := time.Now()
+	type result struct {
+		t0       *nsx.PolicyTier0
+		sessions []nsx.BGPNeighborStatus
+		ok       bool
+	}
+	results := make([]result, len(t0s))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sessions, ok := b.gatewaySessions(ctx, &t0s[i])
+				results[i] = result{t0: &t0s[i], sessions: sessions, ok: ok}
+			}
+		}()
+	}
+feed:
+	for i := range t0s {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var points []*write.Point
+	var down int
+	for _, r := range results {
+		if !r.ok {
+			// Unreadable this cycle (or never fed: ctx cancelled): keep the
+			// previous states so a failed poll is never reported as a
+			// session going down.
+			continue
+		}
+		kind := "t0"
+		if r.t0.IsVRF() {
+			kind = "vrf"
+		}
+		prev := b.prev[r.t0.Path]
+		cur := make(map[string]bgpSession, len(r.sessions))
+		for i := range r.sessions {
+			n := &r.sessions[i]
+			edgeID := n.TransportNode.TargetID
+			edgeName := n.TransportNode.TargetDisplayName
+			if edgeName == "" {
+				edgeName = tnNames[edgeID]
+			}
+			state := strings.ToUpper(strings.TrimSpace(n.ConnectionState))
+			if !n.Established() {
+				down++
+			}
+			points = append(points, influxpkg.BGPNeighborPoint(
+				site, kind, r.t0.DisplayName, r.t0.ID, edgeID, edgeName, n, now,
+			))
+
+			key := edgeID + "|" + n.NeighborAddress
+			cur[key] = bgpSession{state: state, established: n.Established()}
+			old, seen := prev[key]
+			if !seen || old.established == n.Established() {
+				continue
+			}
+			event := "up"
+			if !n.Established() {
+				event = "down"
+				b.logger.Warn("bgp: session down",
+					zap.String("gateway", r.t0.DisplayName),
+					zap.String("edge", edgeName),
+					zap.String("neighbor", n.NeighborAddress),
+					zap.String("remote_as", n.RemoteASNumber),
+					zap.String("state", state),
+				)
+			} else {
+				b.logger.Info("bgp: session up",
+					zap.String("gateway", r.t0.DisplayName),
+					zap.String("edge", edgeName),
+					zap.String("neighbor", n.NeighborAddress),
+				)
+			}
+			points = append(points, influxpkg.BGPEventPoint(
+				site, event, kind, r.t0.DisplayName, edgeName, n.NeighborAddress,
+				n.RemoteASNumber, old.state, state, n.ConnectionDropCount, now,
+			))
+			telemetry.BGPEvents.WithLabelValues(site, event).Inc()
+		}
+		b.prev[r.t0.Path] = cur
+	}
+
+	// Forget gateways that no longer exist.
+	live := make(map[string]struct{}, len(t0s))
+	for i := range t0s {
+		live[t0s[i].Path] = struct{}{}
+	}
+	for p := range b.prev {
+		if _, ok := live[p]; !ok {
+			delete(b.prev, p)
+		}
+	}
+	for p := range b.ls {
+		if _, ok := live[p]; !ok {
+			delete(b.ls, p)
+			delete(b.lsFetched, p)
+		}
+	}
+
+	telemetry.BGPSessionsDown.WithLabelValues(site).Set(float64(down))
+	b.logger.Debug("bgp collected", zap.Int("gateways", len(t0s)), zap.Int("points", len(points)), zap.Int("down", down))
+	return points, nil
+}
+
+// gatewaySessions returns every BGP session of one T0/VRF across its
+// locale-services. ok=false when any read failed (other than a 404 for a
+// gateway or locale-services deleted mid-cycle), so the caller keeps the
+// previous states instead of diffing against a partial view.
+func (b *BGPCollector) gatewaySessions(ctx context.Context, t0 *nsx.PolicyTier0) ([]nsx.BGPNeighborStatus, bool) {
+	lsPaths, err := b.localeServices(ctx, t0.Path)
+	if err != nil {
+		if nsx.IsNotFound(err) {
+			return nil, true
+		}
+		b.logger.Debug("bgp: locale-services failed", zap.String("gateway", t0.DisplayName), zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(b.site, "bgp").Inc()
+		return nil, false
+	}
+	var all []nsx.BGPNeighborStatus
+	for _, p := range lsPaths {
+		sessions, err := b.client.GetBGPNeighborStatus(ctx, p)
+		if err != nil {
+			if nsx.IsNotFound(err) {
+				b.forgetLocaleServices(t0.Path)
+				continue
+			}
+			b.logger.Debug("bgp: neighbors status failed", zap.String("gateway", t0.DisplayName), zap.String("locale_services", p), zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(b.site, "bgp").Inc()
+			return nil, false
+		}
+		all = append(all, sessions...)
+	}
+	return all, true
+}
+
+// localeServices returns the locale-services paths of a gateway, listing
+// them at most once per localeServicesTTL.
+func (b *BGPCollector) localeServices(ctx context.Context, t0Path string) ([]string, error) {
+	b.mu.Lock()
+	paths, ok := b.ls[t0Path]
+	fresh := ok && time.Since(b.lsFetched[t0Path]) < localeServicesTTL
+	b.mu.Unlock()
+	if fresh {
+		return paths, nil
+	}
+
+	items, err := b.client.GetTier0LocaleServices(ctx, t0Path)
+	if err != nil {
+		return nil, err
+	}
+	paths = make([]string, 0, len(items))
+	for _, ls := range items {
+		if ls.Path != "" {
+			paths = append(paths, ls.Path)
+		}
+	}
+	b.mu.Lock()
+	b.ls[t0Path] = paths
+	b.lsFetched[t0Path] = time.Now()
+	b.mu.Unlock()
+	return paths, nil
+}
+
+// forgetLocaleServices drops the cached paths of a gateway so the next poll
+// lists them again (one was deleted).
+func (b *BGPCollector) forgetLocaleServices(t0Path string) {
+	b.mu.Lock()
+	delete(b.ls, t0Path)
+	delete(b.lsFetched, t0Path)
+	b.mu.Unlock()
+}
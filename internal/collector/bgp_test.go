@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+)
+
+// fakeBGP is a Manager with one T0 and one VRF, one locale-services each and
+// one session per gateway on edge-01, whose states the test flips.
+type fakeBGP struct {
+	mu       sync.Mutex
+	state    map[string]string // locale-services path → connection_state
+	listings map[string]int    // T0/VRF path → locale-services listings
+	gone     map[string]bool   // locale-services paths answering 404
+	failing  map[string]bool   // locale-services paths answering 500
+}
+
+func newFakeBGP(t *testing.T) (*BGPCollector, *fakeBGP) {
+	f := &fakeBGP{
+		state: map[string]string{
+			"/infra/tier-0s/t0/locale-services/default":    "ESTABLISHED",
+			"/infra/tier-0s/vrf-a/locale-services/default": "ESTABLISHED",
+		},
+		listings: map[string]int{},
+		gone:     map[string]bool{},
+		failing:  map[string]bool{},
+	}
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/infra/tier-0s": `{"results":[
+			{"id":"t0","display_name":"T0-CORE","path":"/infra/tier-0s/t0"},
+			{"id":"vrf-a","display_name":"T0-CORE-vrf_a","path":"/infra/tier-0s/vrf-a","vrf_config":{"tier0_path":"/infra/tier-0s/t0"}}]}`,
+		"/api/v1/transport-nodes": `{"results":[{"id":"e1","display_name":"edge-01"}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, "/policy/api/v1")
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if gw, ok := strings.CutSuffix(p, "/locale-services"); ok {
+			f.listings[gw]++
+			w.Write([]byte(`{"results":[{"id":"default","path":"` + gw + `/locale-services/default"}]}`))
+			return
+		}
+		ls, ok := strings.CutSuffix(p, "/bgp/neighbors/status")
+		if !ok || f.gone[ls] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if f.failing[ls] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"results":[{"neighbor_address":"10.0.0.1","remote_as_number":"65001",
+			"connection_state":"` + f.state[ls] + `","transport_node":{"target_id":"e1"}}]}`))
+	})
+	return NewBGPCollector(testSite, c, zap.NewNop()), f
+}
+
+func (f *fakeBGP) set(ls, state string) {
+	f.mu.Lock()
+	f.state[ls] = state
+	f.mu.Unlock()
+}
+
+func TestBGPEvents(t *testing.T) {
+	b, f := newFakeBGP(t)
+	poll := func() []*write.Point {
+		t.Helper()
+		points, err := b.CollectBGP(context.Background())
+		if err != nil {
+			t.Fatalf("CollectBGP: %v", err)
+		}
+		return points
+	}
+	events := func(points []*write.Point) []string {
+		var got []string
+		for _, p := range points {
+			if p.Name() == "nsx_bgp_event" {
+				got = append(got, pointTag(p, "gateway_kind")+"/"+pointTag(p, "gateway_name")+"/"+pointTag(p, "event"))
+			}
+		}
+		return got
+	}
+
+	// First poll baselines: neighbor points, no events.
+	points := poll()
+	kinds := map[string]string{}
+	for _, p := range points {
+		if p.Name() == "nsx_bgp_neighbor" {
+			kinds[pointTag(p, "gateway_name")] = pointTag(p, "gateway_kind")
+			if pointTag(p, "edge_name") != "edge-01" {
+				t.Errorf("edge_name = %q, want edge-01 from the transport node list", pointTag(p, "edge_name"))
+			}
+		}
+	}
+	if kinds["T0-CORE"] != "t0" || kinds["T0-CORE-vrf_a"] != "vrf" {
+		t.Fatalf("gateway kinds = %v", kinds)
+	}
+	if got := events(points); len(got) != 0 {
+		t.Fatalf("baseline events = %v", got)
+	}
+
+	// The VRF session drops: one down event, attributed to the VRF.
+	f.set("/infra/tier-0s/vrf-a/locale-services/default", "ACTIVE")
+	if got := events(poll()); len(got) != 1 || got[0] != "vrf/T0-CORE-vrf_a/down" {
+		t.Fatalf("down events = %v", got)
+	}
+	// Still down: no new event.
+	if got := events(poll()); len(got) != 0 {
+		t.Fatalf("repeated events = %v", got)
+	}
+	// A state change between two non-established states is not an event.
+	f.set("/infra/tier-0s/vrf-a/locale-services/default", "CONNECT")
+	if got := events(poll()); len(got) != 0 {
+		t.Fatalf("down→down events = %v", got)
+	}
+	f.set("/infra/tier-0s/vrf-a/locale-services/default", "ESTABLISHED")
+	if got := events(poll()); len(got) != 1 || got[0] != "vrf/T0-CORE-vrf_a/up" {
+		t.Fatalf("up events = %v", got)
+	}
+
+	// A failed read is not a session going down, and keeps the previous
+	// state: the drop seen on the next readable poll is still an event.
+	f.mu.Lock()
+	f.failing["/infra/tier-0s/t0/locale-services/default"] = true
+	f.mu.Unlock()
+	if got := events(poll()); len(got) != 0 {
+		t.Fatalf("events on a failed read = %v", got)
+	}
+	f.mu.Lock()
+	f.failing["/infra/tier-0s/t0/locale-services/default"] = false
+	f.state["/infra/tier-0s/t0/locale-services/default"] = "IDLE"
+	f.mu.Unlock()
+	if got := events(poll()); len(got) != 1 || got[0] != "t0/T0-CORE/down" {
+		t.Fatalf("events after a failed read = %v", got)
+	}
+}
+
+func TestBGPLocaleServicesCache(t *testing.T) {
+	b, f := newFakeBGP(t)
+	for i := 0; i < 3; i++ {
+		if _, err := b.CollectBGP(context.Background()); err != nil {
+			t.Fatalf("CollectBGP: %v", err)
+		}
+	}
+	f.mu.Lock()
+	if f.listings["/infra/tier-0s/t0"] != 1 || f.listings["/infra/tier-0s/vrf-a"] != 1 {
+		t.Fatalf("listings within TTL = %v, want 1 each", f.listings)
+	}
+	f.mu.Unlock()
+
+	// Past the TTL the gateway is listed again.
+	b.mu.Lock()
+	b.lsFetched["/infra/tier-0s/t0"] = time.Now().Add(-localeServicesTTL - time.Second)
+	b.mu.Unlock()
+	// A locale-services deleted mid-cycle (404) drops the cached list.
+	f.mu.Lock()
+	f.gone["/infra/tier-0s/vrf-a/locale-services/default"] = true
+	f.mu.Unlock()
+	if _, err := b.CollectBGP(context.Background()); err != nil {
+		t.Fatalf("CollectBGP: %v", err)
+	}
+	if _, err := b.CollectBGP(context.Background()); err != nil {
+		t.Fatalf("CollectBGP: %v", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.listings["/infra/tier-0s/t0"] != 2 {
+		t.Errorf("t0 listings after TTL = %d, want 2", f.listings["/infra/tier-0s/t0"])
+	}
+	if f.listings["/infra/tier-0s/vrf-a"] != 2 {
+		t.Errorf("vrf listings after a 404 = %d, want 2 (relisted on the next poll)", f.listings["/infra/tier-0s/vrf-a"])
+	}
+}
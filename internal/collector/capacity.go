@@ -30,10 +30,20 @@ type t0Meta struct {
 //   - T1-per-VRF and T1-per-T0 aggregates with configured limits
 //   - Edge cluster ID → display_name resolution (for t1watch messages)
 //   - Segments per parent (T1/VRF/T0) — when capacity.collect_segments=true
+//     (Policy search when available, else the segment list)
 //   - Gateway firewall rules per gateway — when capacity.collect_gw_policies=true
+//     (search aggregate when available, else the gateway-policies list)
 //   - Groups inventory (total + empty) — when capacity.collect_groups=true
-//   - NAT rules per T1 (top-N or all) — when capacity.collect_nat_per_t1=true
+//   - Group effective members, rotating sample — when capacity.group_members_sample>0
+//   - NAT rules per T1 — when capacity.collect_nat_per_t1=true
+//   - Static routes per gateway — when capacity.collect_static_routes=true
+//   - RIB/FIB size per T0/VRF and edge — when capacity.collect_route_tables=true
+//   - Service routers per edge node / edge cluster — when capacity.collect_sr_per_edge=true
+//   - IP pool and IP block usage — when capacity.collect_ip_pools=true
 //   - T1 lifecycle diff + Slack notification via t1watch.Notifier
+//
+// With capacity.incremental the segment, gateway-policy and NAT counts are
+// kept in state_dir (see capacityState) and only recounted for what changed.
 type CapacityCollector struct {
 	site        string
 	client      *nsx.Client
@@ -75,7 +85,12 @@ func (cc *CapacityCollector) Collect(ctx context.Context, now time.Time) (capaci
 	site := cc.site
 
 	// ---- LB credits -------------------------------------------------------
-	if summary, err := cc.client.GetLBNodeUsageSummary(ctx); err != nil {
+	if !cc.client.Supports(nsx.CapLBNodeUsageSummary) {
+		cc.logger.Debug("lb credits skipped: not supported by this NSX release")
+	} else if summary, err := cc.client.GetLBNodeUsageSummary(ctx); err != nil {
+		if nsx.IsUnsupported(err) {
+			cc.client.MarkUnsupported(nsx.CapLBNodeUsageSummary, err.Error())
+		}
 		cc.logger.Warn("lb credits failed", zap.Error(err))
 		telemetry.CollectErrors.WithLabelValues(site, "lb_credits").Inc()
 	} else if summary != nil {
@@ -164,6 +179,7 @@ func (cc *CapacityCollector) Collect(ctx context.Context, now time.Time) (capaci
 		}
 		live = append(live, t1watch.LiveT1{
 			ID:              t.UniqueID,
+			PolicyID:        t.ID,
 			Name:            t.DisplayName,
 			ParentT0ID:      parent.ID,
 			ParentT0Name:    parent.Name,
@@ -221,6 +237,9 @@ func (cc *CapacityCollector) Collect(ctx context.Context, now time.Time) (capaci
 		if snap == nil {
 			snap = &t1watch.Snapshot{Site: site, Known: map[string]t1watch.T1Info{}}
 		}
+		if baselined {
+			live = cc.confirmMissingT1s(ctx, snap, live)
+		}
 		events := t1watch.Detect(snap, live, baselined, vrfCount, t0Count, siteT1Total, vrfLimit, t0Limit, now)
 		telemetry.T1KnownGauge.WithLabelValues(site).Set(float64(len(snap.Known)))
 		if err := t1watch.SaveSnapshot(cc.stateDir, snap); err != nil {
@@ -257,65 +276,67 @@ func (cc *CapacityCollector) Collect(ctx context.Context, now time.Time) (capaci
 		}
 	}
 
+	// ---- Incremental state ----------------------------------------------
+	// st is nil when incremental collection is off; full forces a recount.
+	st, full := cc.loadIncremental(now)
+	t1Changed, t1SetChanged := st.diffT1s(t1s)
+	// A cached map is reused only when no T1 changed (names feed the keys)
+	// and search finds nothing of the kind modified since the watermark.
+	reusable := func(cached bool, query string) bool {
+		return st != nil && !full && cached && !t1SetChanged && cc.unchangedSince(ctx, st, query)
+	}
+
 	// ---- Segments per parent --------------------------------------------
 	if cc.cfg.CollectSegments {
 		telemetry.CapacityExtrasPolls.WithLabelValues(site, "segments").Inc()
-		if segs, err := cc.client.GetPolicySegments(ctx); err != nil {
+		var perParent map[string]int64 // key: "kind|name|id"
+		if reusable(st != nil && st.Segments != nil, "resource_type:Segment OR resource_type:Tier0") {
+			perParent = st.Segments
+			cc.logger.Debug("segments unchanged, re-emitting cached counts")
+		} else if counts, err := cc.segmentsPerParent(ctx, t0ByPath, t1s); err != nil {
 			cc.logger.Warn("policy segments failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "segments").Inc()
 		} else {
-			perParent := map[string]int64{} // key: "kind|name|id"
-			for _, s := range segs {
-				kind, name, id := classifyConnectivityPath(s.ConnectivityPath, t0ByPath, t1s)
-				key := kind + "|" + name + "|" + id
-				perParent[key]++
-			}
-			for key, count := range perParent {
-				parts := strings.SplitN(key, "|", 3)
-				if len(parts) != 3 {
-					continue
-				}
-				capacityPoints = append(capacityPoints, influxpkg.SegmentsPerParentPoint(
-					site, parts[0], parts[1], parts[2], count, now,
-				))
+			perParent = counts
+		}
+		if st != nil {
+			st.Segments = perParent
+		}
+		for key, count := range perParent {
+			parts := strings.SplitN(key, "|", 3)
+			if len(parts) != 3 {
+				continue
 			}
+			capacityPoints = append(capacityPoints, influxpkg.SegmentsPerParentPoint(
+				site, parts[0], parts[1], parts[2], count, now,
+			))
 		}
 	}
 
 	// ---- Gateway firewall policies per gateway --------------------------
 	if cc.cfg.CollectGWPolicies {
 		telemetry.CapacityExtrasPolls.WithLabelValues(site, "gw_policies").Inc()
-		if policies, err := cc.client.GetGatewayPolicies(ctx); err != nil {
+		var perGW map[string]gwPolicyAcc // key: "kind|name|id"
+		if reusable(st != nil && st.GWPolicies != nil, "resource_type:GatewayPolicy OR resource_type:Rule OR resource_type:Tier0") {
+			perGW = st.GWPolicies
+			cc.logger.Debug("gateway policies unchanged, re-emitting cached counts")
+		} else if counts, err := cc.gwPoliciesPerGateway(ctx, t0ByPath, t1s); err != nil {
 			cc.logger.Warn("gateway policies failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "gw_policies").Inc()
 		} else {
-			type acc struct {
-				rules    int64
-				policies int64
-			}
-			perGW := map[string]acc{} // key: "kind|name|id"
-			for _, p := range policies {
-				for _, scope := range p.Scope {
-					kind, name, id := classifyConnectivityPath(scope, t0ByPath, t1s)
-					if kind == "overlay" || kind == "unknown" {
-						continue
-					}
-					k := kind + "|" + name + "|" + id
-					a := perGW[k]
-					a.rules += int64(p.RuleCount)
-					a.policies++
-					perGW[k] = a
-				}
-			}
-			for key, a := range perGW {
-				parts := strings.SplitN(key, "|", 3)
-				if len(parts) != 3 {
-					continue
-				}
-				capacityPoints = append(capacityPoints, influxpkg.FWPerGatewayPoint(
-					site, parts[0], parts[1], parts[2], a.rules, a.policies, now,
-				))
+			perGW = counts
+		}
+		if st != nil {
+			st.GWPolicies = perGW
+		}
+		for key, a := range perGW {
+			parts := strings.SplitN(key, "|", 3)
+			if len(parts) != 3 {
+				continue
 			}
+			capacityPoints = append(capacityPoints, influxpkg.FWPerGatewayPoint(
+				site, parts[0], parts[1], parts[2], a.Rules, a.Policies, now,
+			))
 		}
 	}
 
@@ -334,79 +355,379 @@ func (cc *CapacityCollector) Collect(ctx context.Context, now time.Time) (capaci
 				}
 			}
 			capacityPoints = append(capacityPoints, influxpkg.GroupsInventoryPoint(site, total, empty, now))
+			if cc.cfg.GroupMembersSample > 0 {
+				telemetry.CapacityExtrasPolls.WithLabelValues(site, "group_members").Inc()
+				capacityPoints = append(capacityPoints, cc.collectGroupMembers(ctx, groups, now)...)
+			}
 		}
 	}
 
-	// ---- NAT rules per T1 (expensive — 1 req per T1) ---------------------
+	// ---- NAT rules per T1 (search; 1 req per T1 as fallback) -------------
+	var natCounts map[string]int
 	if cc.cfg.CollectNATPerT1 && len(t1s) > 0 {
 		telemetry.CapacityExtrasPolls.WithLabelValues(site, "nat_per_t1").Inc()
-		pace := time.Duration(cc.cfg.NATPerT1PaceMS) * time.Millisecond
-		parallel := cc.cfg.NATPerT1Parallel
-		if parallel <= 0 {
-			parallel = 4
-		}
-		natResults := cc.fetchNATPerT1(ctx, t1s, pace, parallel)
-		for _, r := range natResults {
-			parent := t0ByPath[r.tier0Path]
+		if st != nil && !full {
+			natCounts = cc.natIncremental(ctx, st, t1s, t1Changed)
+		}
+		if natCounts == nil {
+			paths := make([]string, 0, len(t1s))
+			for i := range t1s {
+				paths = append(paths, t1s[i].Path)
+			}
+			counts, err := cc.countPerGateway(ctx, "nat_per_t1", paths,
+				"resource_type:PolicyNatRule", natRuleGateway, cc.natRuleCount)
+			if err != nil {
+				cc.logger.Warn("nat per t1 failed", zap.Error(err))
+				telemetry.CollectErrors.WithLabelValues(site, "nat_per_t1").Inc()
+				if st != nil {
+					// Nothing to diff against next cycle: recount in full.
+					full = false
+					st.LastFullSync = time.Time{}
+				}
+			}
+			natCounts = counts
+		}
+		for i := range t1s {
+			t1 := &t1s[i]
+			cnt, ok := natCounts[t1.Path]
+			if !ok {
+				continue
+			}
+			parent := t0ByPath[t1.Tier0Path]
 			kind := "t0"
 			if parent.IsVRF {
 				kind = "vrf"
 			}
 			capacityPoints = append(capacityPoints, influxpkg.NATPerT1Point(
-				site, r.t1ID, r.t1Name, kind, parent.Name, int64(r.natCount), now,
+				site, t1.ID, t1.DisplayName, kind, parent.Name, int64(cnt), now,
+			))
+		}
+	}
+
+	// ---- Static routes per gateway (search; 1 req per gateway fallback) --
+	if cc.cfg.CollectStaticRoutes {
+		telemetry.CapacityExtrasPolls.WithLabelValues(site, "static_routes").Inc()
+		paths := make([]string, 0, len(t0s)+len(t1s))
+		for i := range t0s {
+			paths = append(paths, t0s[i].Path)
+		}
+		for i := range t1s {
+			paths = append(paths, t1s[i].Path)
+		}
+		counts, err := cc.countPerGateway(ctx, "static_routes", paths,
+			"resource_type:StaticRoutes",
+			func(r nsx.SearchResult) string { return nsx.GatewayOf(r.Path) },
+			cc.client.GetStaticRouteCount,
+		)
+		if err != nil {
+			cc.logger.Warn("static routes failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "static_routes").Inc()
+		}
+		for _, p := range paths {
+			cnt, ok := counts[p]
+			if !ok {
+				continue
+			}
+			kind, name, id := classifyConnectivityPath(p, t0ByPath, t1s)
+			capacityPoints = append(capacityPoints, influxpkg.StaticRoutesPerGatewayPoint(
+				site, kind, name, id, int64(cnt), now,
 			))
 		}
 	}
 
+	// ---- Route tables (RIB + FIB) per T0/VRF and edge ------------------
+	if cc.cfg.CollectRouteTables && len(t0s) > 0 {
+		telemetry.CapacityExtrasPolls.WithLabelValues(site, "route_tables").Inc()
+		capacityPoints = append(capacityPoints, cc.collectRouteTables(ctx, t0s, now)...)
+	}
+
+	// ---- Service routers per edge node / edge cluster ------------------
+	if cc.cfg.CollectSRPerEdge {
+		telemetry.CapacityExtrasPolls.WithLabelValues(site, "sr_per_edge").Inc()
+		capacityPoints = append(capacityPoints, cc.collectServiceRouters(ctx, now)...)
+	}
+
+	// ---- IP pools and IP blocks -----------------------------------------
+	if cc.cfg.CollectIPPools {
+		telemetry.CapacityExtrasPolls.WithLabelValues(site, "ip_pools").Inc()
+		capacityPoints = append(capacityPoints, cc.collectIPPools(ctx, now)...)
+	}
+
+	if st != nil {
+		st.T1s = make(map[string]t1CapacityState, len(t1s))
+		for i := range t1s {
+			n := -1
+			if c, ok := natCounts[t1s[i].Path]; ok {
+				n = c
+			}
+			st.T1s[t1s[i].Path] = t1CapacityState{Revision: t1s[i].Revision, NATRules: n}
+		}
+		if full {
+			st.LastFullSync = now
+		}
+		st.Watermark = now.Add(-changeWatermarkSkew).UnixMilli()
+		if err := cc.saveState(st); err != nil {
+			cc.logger.Warn("capacity save state failed", zap.Error(err))
+		}
+	}
+
 	return capacityPoints, points
 }
 
-type natResult struct {
-	t1ID      string
-	t1Name    string
-	tier0Path string
-	natCount  int
+// maxT1DeleteConfirmations bounds the per-cycle GETs spent confirming T1s
+// missing from the listing; the rest stay known and are retried next cycle.
+const maxT1DeleteConfirmations = 100
+
+// confirmMissingT1s checks every snapshot T1 absent from the live listing
+// with a direct GET. Only a 404 lets the detector forget it (and emit
+// "deleted"); if the router still answers, or the Manager fails with a
+// 5xx/timeout, it is carried over as live so a glitchy listing never
+// produces phantom delete events. The snapshot is keyed by unique_id, so
+// the Policy GET uses the stored Policy id; entries saved before it was
+// kept are checked on the legacy logical router instead.
+func (cc *CapacityCollector) confirmMissingT1s(ctx context.Context, snap *t1watch.Snapshot, live []t1watch.LiveT1) []t1watch.LiveT1 {
+	liveIDs := make(map[string]struct{}, len(live))
+	for _, t := range live {
+		liveIDs[t.ID] = struct{}{}
+	}
+	checked := 0
+	for id, info := range snap.Known {
+		if _, ok := liveIDs[id]; ok {
+			continue
+		}
+		keep := true
+		if checked < maxT1DeleteConfirmations {
+			checked++
+			var err error
+			if info.PolicyID != "" {
+				_, err = cc.client.GetPolicyTier1(ctx, info.PolicyID)
+			} else {
+				_, err = cc.client.GetLogicalRouterStatus(ctx, id)
+			}
+			switch {
+			case nsx.IsNotFound(err):
+				keep = false
+			case err != nil:
+				cc.logger.Debug("t1watch: delete confirmation failed, keeping T1",
+					zap.String("t1_id", id),
+					zap.String("t1_name", info.Name),
+					zap.Error(err),
+				)
+			default:
+				cc.logger.Warn("t1watch: T1 missing from listing but still exists, keeping",
+					zap.String("t1_id", id),
+					zap.String("t1_name", info.Name),
+				)
+			}
+		}
+		if keep {
+			live = append(live, t1watch.LiveT1{
+				ID:              info.ID,
+				PolicyID:        info.PolicyID,
+				Name:            info.Name,
+				ParentT0ID:      info.ParentT0ID,
+				ParentT0Name:    info.ParentT0Name,
+				ParentKind:      info.ParentKind,
+				EdgeClusterID:   info.EdgeClusterID,
+				EdgeClusterName: info.EdgeClusterName,
+			})
+		}
+	}
+	return live
+}
+
+// searchEnabled reports whether per-gateway counts may use the Policy Search
+// API: allowed by capacity.use_search and not known unsupported.
+func (cc *CapacityCollector) searchEnabled() bool {
+	return (cc.cfg.UseSearch == nil || *cc.cfg.UseSearch) && cc.client.Supports(nsx.CapPolicySearch)
 }
 
-// fetchNATPerT1 issues one NAT-count request per T1 with bounded parallelism
-// and inter-request pacing. Errors are logged and counted but never abort the
-// batch — partial results are written.
-func (cc *CapacityCollector) fetchNATPerT1(ctx context.Context, t1s []nsx.PolicyTier1, pace time.Duration, parallel int) []natResult {
-	sem := make(chan struct{}, parallel)
-	out := make([]natResult, 0, len(t1s))
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	for i := range t1s {
-		t1 := t1s[i]
-		// Pace requests globally to keep the NSX Manager happy.
-		if pace > 0 && i > 0 {
-			select {
-			case <-ctx.Done():
-				wg.Wait()
-				return out
-			case <-time.After(pace):
+// natRuleGateway keys a PolicyNatRule search hit by its T1. USER section
+// only, matching the fan-out endpoint.
+func natRuleGateway(r nsx.SearchResult) string {
+	if !strings.Contains(r.ParentPath, "/nat/USER") {
+		return ""
+	}
+	return nsx.GatewayOf(r.ParentPath)
+}
+
+// natRuleCount is the per-T1 fan-out for NAT rules.
+func (cc *CapacityCollector) natRuleCount(ctx context.Context, t1Path string) (int, error) {
+	return cc.client.GetTier1NATRuleCount(ctx, nsx.LastPathSegment(t1Path))
+}
+
+// countPerGateway counts child objects per gateway path. With search enabled
+// it runs query through the Policy Search API and groups hits by key; every
+// gateway in paths is reported (0 when it has no hits). When the Manager does
+// not implement search (older release, or the call answers 404/501) the
+// capability is marked off for a while (see nsx.MarkUnsupported) and fanOut is called
+// once per path instead. Any other search error is
+// returned without falling back: a Manager failing a few search pages would
+// not cope better with thousands of per-gateway calls.
+func (cc *CapacityCollector) countPerGateway(
+	ctx context.Context,
+	component string,
+	paths []string,
+	query string,
+	key func(nsx.SearchResult) string,
+	fanOut func(context.Context, string) (int, error),
+) (map[string]int, error) {
+	if cc.searchEnabled() {
+		hits, err := cc.client.CountByParent(ctx, query, key)
+		if err == nil {
+			counts := make(map[string]int, len(paths))
+			for _, p := range paths {
+				counts[p] = hits[p]
+			}
+			cc.logger.Debug("counted via search", zap.String("component", component), zap.Int("gateways", len(paths)))
+			return counts, nil
+		}
+		if !nsx.IsUnsupported(err) {
+			return nil, err
+		}
+		cc.client.MarkUnsupported(nsx.CapPolicySearch, err.Error())
+		cc.logger.Info("policy search unsupported, falling back to per-gateway requests",
+			zap.String("component", component), zap.Error(err))
+	}
+	return cc.fanOutCount(ctx, component, paths, fanOut), nil
+}
+
+// segmentsPerParent counts /infra segments per parent, keyed
+// "kind|name|id" (see classifyConnectivityPath). With search enabled a
+// paginated Segment query returning only the connectivity path replaces the
+// full segment list; like countPerGateway, only a 404/501 from search falls
+// back to the list.
+func (cc *CapacityCollector) segmentsPerParent(ctx context.Context, t0ByPath map[string]t0Meta, t1s []nsx.PolicyTier1) (map[string]int64, error) {
+	perParent := map[string]int64{}
+	add := func(connectivityPath string) {
+		kind, name, id := classifyConnectivityPath(connectivityPath, t0ByPath, t1s)
+		perParent[kind+"|"+name+"|"+id]++
+	}
+	if cc.searchEnabled() {
+		err := cc.client.SearchQuery(ctx, "resource_type:Segment", func(page []nsx.SearchResult) {
+			for _, r := range page {
+				// Same scope as the list: tier-1 local segments are left out.
+				if r.ParentPath == "/infra" {
+					add(r.ConnectivityPath)
+				}
 			}
+		})
+		if err == nil {
+			cc.logger.Debug("counted via search", zap.String("component", "segments"), zap.Int("parents", len(perParent)))
+			return perParent, nil
 		}
+		if !nsx.IsUnsupported(err) {
+			return nil, err
+		}
+		cc.client.MarkUnsupported(nsx.CapPolicySearch, err.Error())
+		cc.logger.Info("policy search unsupported, falling back to the segment list", zap.Error(err))
+		perParent = map[string]int64{}
+	}
+	segs, err := cc.client.GetPolicySegments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range segs {
+		add(s.ConnectivityPath)
+	}
+	return perParent, nil
+}
+
+// gwPoliciesPerGateway tallies gateway firewall policies and rules per
+// gateway in their scope, keyed "kind|name|id". When the Manager supports
+// /search/aggregate, one paginated call returns every gateway policy with
+// its rule count (Rule joined on parent_path); otherwise, or on a 404/501,
+// the gateway-policies list with include_rule_count is used.
+func (cc *CapacityCollector) gwPoliciesPerGateway(ctx context.Context, t0ByPath map[string]t0Meta, t1s []nsx.PolicyTier1) (map[string]gwPolicyAcc, error) {
+	perGW := map[string]gwPolicyAcc{}
+	add := func(scope []string, rules int) {
+		for _, sc := range scope {
+			kind, name, id := classifyConnectivityPath(sc, t0ByPath, t1s)
+			if kind == "overlay" || kind == "unknown" {
+				continue
+			}
+			k := kind + "|" + name + "|" + id
+			a := perGW[k]
+			a.Rules += int64(rules)
+			a.Policies++
+			perGW[k] = a
+		}
+	}
+	if cc.searchEnabled() && cc.client.Supports(nsx.CapSearchAggregate) {
+		err := cc.client.SearchAggregate(ctx, nsx.AggregateRequest{
+			Primary: nsx.AggregateResource{ResourceType: "GatewayPolicy"},
+			Related: []nsx.AggregateResource{{
+				ResourceType:  "Rule",
+				JoinCondition: "parent_path:path",
+				Alias:         "rules",
+			}},
+		}, func(page []nsx.AggregateResult) {
+			for i := range page {
+				// Same scope as the list: the default domain only.
+				if page[i].Primary.ParentPath == "/infra/domains/default" {
+					add(page[i].Primary.Scope, page[i].RelatedCount("rules"))
+				}
+			}
+		})
+		if err == nil {
+			cc.logger.Debug("counted via search aggregate", zap.String("component", "gw_policies"), zap.Int("gateways", len(perGW)))
+			return perGW, nil
+		}
+		if !nsx.IsUnsupported(err) {
+			return nil, err
+		}
+		cc.client.MarkUnsupported(nsx.CapSearchAggregate, err.Error())
+		cc.logger.Info("search aggregate unsupported, falling back to the gateway-policies list", zap.Error(err))
+		perGW = map[string]gwPolicyAcc{}
+	}
+	policies, err := cc.client.GetGatewayPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		add(p.Scope, p.RuleCount)
+	}
+	return perGW, nil
+}
+
+// fanOutCount calls count once per path from a worker pool sized to the
+// client's in-flight cap; pacing is left to the client's shared limiter.
+// Errors are counted but never abort the batch — partial results are
+// returned.
+func (cc *CapacityCollector) fanOutCount(ctx context.Context, component string, paths []string, count func(context.Context, string) (int, error)) map[string]int {
+	jobs := make(chan string)
+	out := make(map[string]int, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < cc.client.MaxInFlight(); w++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-			cnt, err := cc.client.GetTier1NATRuleCount(ctx, t1.ID)
-			if err != nil {
-				telemetry.CollectErrors.WithLabelValues(cc.site, "nat_per_t1").Inc()
-				return
+			for p := range jobs {
+				cnt, err := count(ctx, p)
+				if err != nil {
+					// 404: gateway deleted between the listing and this
+					// call — nothing to report, not an error.
+					if !nsx.IsNotFound(err) {
+						telemetry.CollectErrors.WithLabelValues(cc.site, component).Inc()
+					}
+					continue
+				}
+				mu.Lock()
+				out[p] = cnt
+				mu.Unlock()
 			}
-			mu.Lock()
-			out = append(out, natResult{
-				t1ID:      t1.ID,
-				t1Name:    t1.DisplayName,
-				tier0Path: t1.Tier0Path,
-				natCount:  cnt,
-			})
-			mu.Unlock()
 		}()
 	}
+feed:
+	for _, p := range paths {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- p:
+		}
+	}
+	close(jobs)
 	wg.Wait()
 	return out
 }
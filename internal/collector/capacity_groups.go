@@ -0,0 +1,280 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// groupMembersState is the rotating sampler's memory, persisted in
+// <state_dir>/group-members-<site>.json.
+type groupMembersState struct {
+	Site    string    `json:"site"`
+	Updated time.Time `json:"updated"`
+	// After is the path of the last group sampled; the next cycle resumes
+	// at the first group path sorting after it.
+	After string `json:"after"`
+	// Groups is keyed by group path.
+	Groups map[string]groupMembers `json:"groups"`
+}
+
+// groupMembers is the last effective member sample of one group.
+type groupMembers struct {
+	VMs      int64     `json:"vms"`
+	IPs      int64     `json:"ips"`
+	Segments int64     `json:"segments"`
+	Ports    int64     `json:"ports"`
+	Sampled  time.Time `json:"sampled"`
+}
+
+func (g groupMembers) total() int64 { return g.VMs + g.IPs + g.Segments + g.Ports }
+
+// collectGroupMembers reads the effective members of the next
+// capacity.group_members_sample groups (by path, wrapping around) and,
+// from every sample on file, reports the groups whose criteria match no
+// member, the oversized ones and the capacity.group_rules_top_n groups
+// referenced by the most DFW rules. A group whose read fails, or whose
+// member kinds all answer 404 (deleted since the listing), keeps its
+// previous sample. Returns nsx_group_members (flagged groups only) and
+// nsx_group_members_summary points for the capacity bucket.
+func (cc *CapacityCollector) collectGroupMembers(ctx context.Context, groups []nsx.PolicyGroup, now time.Time) []*write.Point {
+	site := cc.site
+	st, err := cc.loadGroupMembersState()
+	if err != nil {
+		cc.logger.Warn("group members: load state failed, sampling restarts", zap.Error(err))
+	}
+
+	sorted := make([]*nsx.PolicyGroup, 0, len(groups))
+	for i := range groups {
+		if groups[i].Path != "" {
+			sorted = append(sorted, &groups[i])
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	batch := nextGroupBatch(sorted, st.After, cc.cfg.GroupMembersSample)
+
+	type result struct {
+		m  groupMembers
+		ok bool
+	}
+	results := make([]result, len(batch))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cc.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				g := batch[i]
+				m := groupMembers{Sampled: now}
+				ok, found := true, false
+				for _, kind := range nsx.GroupMemberKinds {
+					n, err := cc.client.GetGroupMemberCount(ctx, g.ID, kind)
+					if err != nil {
+						if nsx.IsNotFound(err) {
+							continue
+						}
+						cc.logger.Debug("group members failed", zap.String("group", g.DisplayName), zap.String("kind", kind), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "group_members").Inc()
+						ok = false
+						break
+					}
+					found = true
+					switch kind {
+					case "virtual-machines":
+						m.VMs = int64(n)
+					case "ip-addresses":
+						m.IPs = int64(n)
+					case "segments":
+						m.Segments = int64(n)
+					case "segment-ports":
+						m.Ports = int64(n)
+					}
+				}
+				if ok && !found {
+					cc.logger.Debug("group members not found, sample kept", zap.String("group", g.DisplayName))
+					ok = false
+				}
+				results[i] = result{m: m, ok: ok}
+			}
+		}()
+	}
+	fed := 0
+feed:
+	for i := range batch {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+			fed++
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var sampled int64
+	for i, r := range results {
+		if r.ok {
+			st.Groups[batch[i].Path] = r.m
+			sampled++
+		}
+	}
+	// Resume after the last group handed to a worker: on cancellation the
+	// rest of the batch goes first next cycle.
+	if fed > 0 {
+		st.After = batch[fed-1].Path
+	}
+	live := make(map[string]*nsx.PolicyGroup, len(sorted))
+	for _, g := range sorted {
+		live[g.Path] = g
+	}
+	for path := range st.Groups {
+		if live[path] == nil {
+			delete(st.Groups, path)
+		}
+	}
+	if err := cc.saveGroupMembersState(st); err != nil {
+		cc.logger.Warn("group members: save state failed", zap.Error(err))
+	}
+
+	// DFW rule references, for the top-N ranking. A failed count only skips
+	// the ranking for this cycle: the rule search paginates over every rule
+	// and can fail on its own without the search API being unsupported.
+	var ruleRefs map[string]int
+	topRules := map[string]bool{}
+	maxRules := int64(-1)
+	if cc.searchEnabled() {
+		ruleRefs, err = cc.client.CountDFWRuleGroupRefs(ctx)
+		if err != nil {
+			cc.logger.Warn("group members: dfw rule references failed, ranking skipped", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "group_members").Inc()
+		}
+	} else {
+		cc.logger.Debug("group members: policy search off, dfw rule ranking skipped")
+	}
+	ranked := make([]string, 0, len(ruleRefs))
+	for path, n := range ruleRefs {
+		if live[path] != nil && n > 0 {
+			ranked = append(ranked, path)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ruleRefs[ranked[i]] != ruleRefs[ranked[j]] {
+			return ruleRefs[ranked[i]] > ruleRefs[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > cc.cfg.GroupRulesTopN {
+		ranked = ranked[:cc.cfg.GroupRulesTopN]
+	}
+	for _, path := range ranked {
+		topRules[path] = true
+	}
+	if ruleRefs != nil {
+		maxRules = 0
+	}
+	if len(ranked) > 0 {
+		maxRules = int64(ruleRefs[ranked[0]])
+	}
+
+	var points []*write.Point
+	var zero, oversized int64
+	for _, g := range sorted {
+		m, known := st.Groups[g.Path]
+		isZero := known && len(g.Expression) > 0 && m.total() == 0
+		isBig := known && cc.cfg.GroupMembersOversized > 0 && m.total() > cc.cfg.GroupMembersOversized
+		if isZero {
+			zero++
+		}
+		if isBig {
+			oversized++
+		}
+		if !isZero && !isBig && !topRules[g.Path] {
+			continue
+		}
+		if !known {
+			m = groupMembers{VMs: -1, IPs: -1, Segments: -1, Ports: -1}
+		}
+		dfwRules := int64(-1)
+		if ruleRefs != nil {
+			dfwRules = int64(ruleRefs[g.Path])
+		}
+		points = append(points, influxpkg.GroupMembersPoint(
+			site, g.ID, g.DisplayName, m.VMs, m.IPs, m.Segments, m.Ports,
+			dfwRules, isZero, isBig, m.Sampled, now,
+		))
+	}
+	points = append(points, influxpkg.GroupMembersSummaryPoint(
+		site, int64(len(sorted)), sampled, int64(len(st.Groups)), zero, oversized, maxRules, now,
+	))
+	cc.logger.Debug("group members sampled",
+		zap.Int("batch", len(batch)),
+		zap.Int64("sampled", sampled),
+		zap.Int("known", len(st.Groups)),
+		zap.Int64("zero_members", zero),
+		zap.Int64("oversized", oversized),
+	)
+	return points
+}
+
+// nextGroupBatch returns up to n groups of sorted (ascending by path)
+// starting after the path after, wrapping around once.
+func nextGroupBatch(sorted []*nsx.PolicyGroup, after string, n int) []*nsx.PolicyGroup {
+	if n <= 0 || len(sorted) == 0 {
+		return nil
+	}
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	start := sort.Search(len(sorted), func(i int) bool { return sorted[i].Path > after })
+	batch := make([]*nsx.PolicyGroup, 0, n)
+	for i := 0; i < n; i++ {
+		batch = append(batch, sorted[(start+i)%len(sorted)])
+	}
+	return batch
+}
+
+func (cc *CapacityCollector) groupMembersStatePath() string {
+	safeSite := strings.ToLower(strings.ReplaceAll(cc.site, "/", "_"))
+	return filepath.Join(cc.stateDir, "group-members-"+safeSite+".json")
+}
+
+func (cc *CapacityCollector) loadGroupMembersState() (*groupMembersState, error) {
+	fresh := &groupMembersState{Site: cc.site, Groups: map[string]groupMembers{}}
+	data, err := os.ReadFile(cc.groupMembersStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return fresh, err
+	}
+	var st groupMembersState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fresh, err
+	}
+	if st.Groups == nil {
+		st.Groups = map[string]groupMembers{}
+	}
+	return &st, nil
+}
+
+func (cc *CapacityCollector) saveGroupMembersState(st *groupMembersState) error {
+	st.Updated = time.Unix(time.Now().Unix(), 0).UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cc.groupMembersStatePath(), data)
+}
@@ -0,0 +1,221 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	"nsx-collector/internal/nsx"
+)
+
+func TestCollectGroupMembers(t *testing.T) {
+	members := map[string]string{ // group id -> virtual-machines result_count
+		"g-empty": "0",
+		"g-big":   "1500",
+		"g-web":   "12",
+	}
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/search/query": `{"results":[
+			{"path":"/infra/domains/default/security-policies/app/rules/r1","parent_path":"/infra/domains/default/security-policies/app",
+			 "source_groups":["/infra/domains/default/groups/g-web"],"destination_groups":["ANY"],"scope":["/infra/domains/default/groups/g-web"]},
+			{"path":"/infra/domains/default/security-policies/app/rules/r2","parent_path":"/infra/domains/default/security-policies/app",
+			 "source_groups":["ANY"],"destination_groups":["/infra/domains/default/groups/g-web"],"scope":["ANY"]},
+			{"path":"/infra/domains/default/gateway-policies/gw/rules/r3","parent_path":"/infra/domains/default/gateway-policies/gw",
+			 "source_groups":["/infra/domains/default/groups/g-big"],"destination_groups":["ANY"],"scope":["ANY"]}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/policy/api/v1/infra/domains/default/groups/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		if len(parts) == 3 && parts[2] == "virtual-machines" {
+			w.Write([]byte(`{"result_count":` + members[parts[0]] + `}`))
+			return
+		}
+		w.Write([]byte(`{"result_count":0}`))
+	})
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectGroups:         true,
+		GroupMembersSample:    2,
+		GroupMembersOversized: 1000,
+		GroupRulesTopN:        1,
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+	expr := []byte(`{"resource_type":"Condition"}`)
+	groups := []nsx.PolicyGroup{
+		{ID: "g-web", DisplayName: "WEB", Path: "/infra/domains/default/groups/g-web", Expression: []json.RawMessage{expr}},
+		{ID: "g-big", DisplayName: "BIG", Path: "/infra/domains/default/groups/g-big", Expression: []json.RawMessage{expr}},
+		{ID: "g-empty", DisplayName: "EMPTY", Path: "/infra/domains/default/groups/g-empty", Expression: []json.RawMessage{expr}},
+	}
+
+	type row struct {
+		total, dfw, zero, big int64
+	}
+	run := func() (map[string]row, map[string]int64) {
+		got := map[string]row{}
+		summary := map[string]int64{}
+		for _, p := range cc.collectGroupMembers(context.Background(), groups, time.Now()) {
+			if p.Name() == "nsx_group_members_summary" {
+				for _, f := range p.FieldList() {
+					if v, ok := f.Value.(int64); ok {
+						summary[f.Key] = v
+					}
+				}
+				continue
+			}
+			got[pointTag(p, "group_name")] = row{
+				total: pointField(p, "total"),
+				dfw:   pointField(p, "dfw_rules"),
+				zero:  pointField(p, "zero_members"),
+				big:   pointField(p, "oversized"),
+			}
+		}
+		return got, summary
+	}
+
+	// First cycle samples BIG and EMPTY (sorted by path); WEB is reported
+	// for its DFW rules but not sampled yet. The gateway rule on BIG is
+	// not a DFW reference.
+	got, summary := run()
+	want := map[string]row{
+		"BIG":   {total: 1500, big: 1},
+		"EMPTY": {total: 0, zero: 1},
+		"WEB":   {total: -1, dfw: 2},
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("cycle 1 %s = %+v, want %+v", name, got[name], w)
+		}
+	}
+	if summary["known"] != 2 || summary["sampled"] != 2 || summary["max_dfw_rules"] != 2 {
+		t.Errorf("cycle 1 summary = %v", summary)
+	}
+
+	// Second cycle wraps around: WEB, then BIG again.
+	got, summary = run()
+	if got["WEB"] != (row{total: 12, dfw: 2}) {
+		t.Errorf("cycle 2 WEB = %+v", got["WEB"])
+	}
+	if summary["known"] != 3 || summary["zero_members"] != 1 || summary["oversized"] != 1 {
+		t.Errorf("cycle 2 summary = %v", summary)
+	}
+}
+
+func TestCollectGroupMembersRuleSearchFails(t *testing.T) {
+	c := newFakeNSX(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/policy/api/v1/search/query" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"result_count":0}`))
+	})
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectGroups:      true,
+		GroupMembersSample: 1,
+		GroupRulesTopN:     1,
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+	expr := []byte(`{"resource_type":"Condition"}`)
+	groups := []nsx.PolicyGroup{
+		{ID: "g-empty", DisplayName: "EMPTY", Path: "/infra/domains/default/groups/g-empty", Expression: []json.RawMessage{expr}},
+	}
+
+	// The ranking is skipped for the cycle; the rest is still reported and
+	// the site-wide search capability is left alone.
+	var dfw, maxDFW int64
+	for _, p := range cc.collectGroupMembers(context.Background(), groups, time.Now()) {
+		if p.Name() == "nsx_group_members_summary" {
+			maxDFW = pointField(p, "max_dfw_rules")
+			continue
+		}
+		dfw = pointField(p, "dfw_rules")
+	}
+	if dfw != -1 || maxDFW != -1 {
+		t.Errorf("dfw_rules = %d, max_dfw_rules = %d, want -1 (not counted)", dfw, maxDFW)
+	}
+	if !c.Supports(nsx.CapPolicySearch) {
+		t.Errorf("a failed rule search marked policy search unsupported")
+	}
+}
+
+func TestCollectGroupMembersNotFound(t *testing.T) {
+	// g-gone was deleted after the listing: every member kind is a 404.
+	c := newFakeNSX(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/groups/g-gone/") || r.URL.Path == "/policy/api/v1/search/query" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"result_count":3}`))
+	})
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectGroups:      true,
+		GroupMembersSample: 2,
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+	expr := []byte(`{"resource_type":"Condition"}`)
+	groups := []nsx.PolicyGroup{
+		{ID: "g-gone", DisplayName: "GONE", Path: "/infra/domains/default/groups/g-gone", Expression: []json.RawMessage{expr}},
+		{ID: "g-web", DisplayName: "WEB", Path: "/infra/domains/default/groups/g-web", Expression: []json.RawMessage{expr}},
+	}
+
+	for _, p := range cc.collectGroupMembers(context.Background(), groups, time.Now()) {
+		if p.Name() == "nsx_group_members_summary" {
+			if got := pointField(p, "zero_members"); got != 0 {
+				t.Errorf("zero_members = %d, want 0 (a 404 group is not empty)", got)
+			}
+			if got := pointField(p, "sampled"); got != 1 {
+				t.Errorf("sampled = %d, want 1", got)
+			}
+			continue
+		}
+		t.Errorf("unexpected group point for %s", pointTag(p, "group_name"))
+	}
+	st, err := cc.loadGroupMembersState()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if _, ok := st.Groups["/infra/domains/default/groups/g-gone"]; ok {
+		t.Errorf("404 group recorded as sampled: %+v", st.Groups)
+	}
+}
+
+func TestCollectGroupMembersCancelledKeepsPosition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The only worker is busy on the first group when the cycle is
+		// cancelled, so the rest of the batch is never handed out.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		w.Write([]byte(`{"result_count":1}`))
+	}))
+	defer srv.Close()
+	c, err := nsx.NewClient(nsx.Options{BaseURL: srv.URL, RateLimit: nsx.LimiterConfig{MaxInFlight: 1}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectGroups:      true,
+		GroupMembersSample: 3,
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+	groups := []nsx.PolicyGroup{
+		{ID: "g-a", Path: "/infra/domains/default/groups/g-a"},
+		{ID: "g-b", Path: "/infra/domains/default/groups/g-b"},
+		{ID: "g-c", Path: "/infra/domains/default/groups/g-c"},
+	}
+
+	cc.collectGroupMembers(ctx, groups, time.Now())
+	st, err := cc.loadGroupMembersState()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if st.After != "/infra/domains/default/groups/g-a" {
+		t.Fatalf("after = %q, want the last group fed (g-a)", st.After)
+	}
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/t1watch"
+	"nsx-collector/internal/telemetry"
+)
+
+// collectIPPools returns one nsx_ip_pool point per Policy IP pool (usage
+// from pool_usage) and one nsx_ip_block point per IP block, whose
+// allocation is the sum of the pool subnets carved from it. Pool subnets
+// are read from a worker pool sized to the client's in-flight cap; a pool
+// whose subnets fail is still reported (subnet counts 0) but its block
+// subnets are missing from the block totals this cycle.
+func (cc *CapacityCollector) collectIPPools(ctx context.Context, now time.Time) []*write.Point {
+	site := cc.site
+	pools, err := cc.client.GetIPPools(ctx)
+	if err != nil {
+		cc.logger.Warn("ip pools failed", zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(site, "ip_pools").Inc()
+		return nil
+	}
+
+	type poolSubnets struct {
+		total, fromBlock int64
+	}
+	var (
+		mu         sync.Mutex
+		perPool    = make([]poolSubnets, len(pools))
+		blockAlloc = map[string]nsx.IPCount{} // key: IP block path
+		blockNets  = map[string]int64{}
+		wg         sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < cc.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				subnets, err := cc.client.GetIPPoolSubnets(ctx, pools[i].ID)
+				if err != nil {
+					if !nsx.IsNotFound(err) {
+						cc.logger.Debug("ip pool subnets failed", zap.String("pool", pools[i].DisplayName), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "ip_pools").Inc()
+					}
+					continue
+				}
+				ps := poolSubnets{total: int64(len(subnets))}
+				mu.Lock()
+				for j := range subnets {
+					s := &subnets[j]
+					if !s.FromBlock() || s.IPBlockPath == "" {
+						continue
+					}
+					ps.fromBlock++
+					blockAlloc[s.IPBlockPath] = blockAlloc[s.IPBlockPath].Add(s.Size)
+					blockNets[s.IPBlockPath]++
+				}
+				mu.Unlock()
+				perPool[i] = ps
+			}
+		}()
+	}
+feed:
+	for i := range pools {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	poolThreshold := t1watch.LimitResolver(cc.cfg.IPPoolThresholdPctDefault, cc.cfg.IPPoolThresholds)
+	blockThreshold := t1watch.LimitResolver(cc.cfg.IPPoolThresholdPctDefault, cc.cfg.IPBlockThresholds)
+	points := make([]*write.Point, 0, len(pools))
+	for i := range pools {
+		p := &pools[i]
+		points = append(points, influxpkg.IPPoolPoint(
+			site, p, perPool[i].total, perPool[i].fromBlock, poolThreshold(p.DisplayName), now,
+		))
+	}
+
+	blocks, err := cc.client.GetIPBlocks(ctx)
+	if err != nil {
+		cc.logger.Warn("ip blocks failed", zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(site, "ip_pools").Inc()
+	}
+	for i := range blocks {
+		b := &blocks[i]
+		points = append(points, influxpkg.IPBlockPoint(
+			site, b, int64(blockAlloc[b.Path]), blockNets[b.Path], blockThreshold(b.DisplayName), now,
+		))
+	}
+	cc.logger.Debug("ip pools collected",
+		zap.Int("pools", len(pools)),
+		zap.Int("blocks", len(blocks)),
+	)
+	return points
+}
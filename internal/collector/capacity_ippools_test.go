@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+)
+
+func TestCollectIPPools(t *testing.T) {
+	const blockA, blockB = "/infra/ip-blocks/block-a", "/infra/ip-blocks/block-b"
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/infra/ip-pools": `{"results":[
+			{"id":"p1","display_name":"TEP-EDGE","pool_usage":{"total_ips":100,"allocated_ip_allocations":85}},
+			{"id":"p2","display_name":"SHARED","pool_usage":{"total_ips":100,"allocated_ip_allocations":50}},
+			{"id":"p3","display_name":"BROKEN","pool_usage":{"total_ips":10,"allocated_ip_allocations":1}}]}`,
+		"/policy/api/v1/infra/ip-pools/p1/ip-subnets": `{"results":[
+			{"id":"s1","resource_type":"IpAddressPoolBlockSubnet","ip_block_path":"` + blockA + `","size":64},
+			{"id":"s2","resource_type":"IpAddressPoolBlockSubnet","ip_block_path":"` + blockA + `","size":"64"}]}`,
+		"/policy/api/v1/infra/ip-pools/p2/ip-subnets": `{"results":[
+			{"id":"s3","resource_type":"IpAddressPoolStaticSubnet","cidr":"192.168.0.0/28"},
+			{"id":"s4","resource_type":"IpAddressPoolBlockSubnet","ip_block_path":"` + blockB + `","size":32}]}`,
+		"/policy/api/v1/infra/ip-blocks": `{"results":[
+			{"id":"block-a","display_name":"SHARED","path":"` + blockA + `","cidr":"10.0.0.0/24"},
+			{"id":"block-b","display_name":"BLOCK-B","path":"` + blockB + `","cidr":"10.1.0.0/24"}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		// p3's subnets (carved from block-b) cannot be read this cycle.
+		if r.URL.Path == "/policy/api/v1/infra/ip-pools/p3/ip-subnets" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectIPPools:            true,
+		IPPoolThresholdPctDefault: 80,
+		IPPoolThresholds:          map[string]int64{"SHARED": 40},
+		IPBlockThresholds:         map[string]int64{"BLOCK-B": 10},
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+	type row struct {
+		allocated, subnets, blockSubnets, threshold, over int64
+	}
+	got := map[string]row{}
+	for _, p := range cc.collectIPPools(context.Background(), time.Now()) {
+		name := p.Name() + "/" + pointTag(p, "pool_name") + pointTag(p, "block_name")
+		got[name] = row{
+			allocated:    pointField(p, "allocated"),
+			subnets:      pointField(p, "subnets"),
+			blockSubnets: pointField(p, "block_subnets"),
+			threshold:    pointField(p, "threshold_pct"),
+			over:         pointField(p, "over_threshold"),
+		}
+	}
+	want := map[string]row{
+		"nsx_ip_pool/TEP-EDGE": {allocated: 85, subnets: 2, blockSubnets: 2, threshold: 80, over: 1},
+		"nsx_ip_pool/SHARED":   {allocated: 50, subnets: 2, blockSubnets: 1, threshold: 40, over: 1},
+		// A failed subnet read still reports the pool, without subnets.
+		"nsx_ip_pool/BROKEN": {allocated: 1, subnets: 0, blockSubnets: 0, threshold: 80, over: 0},
+		// Blocks sum the pool subnets carved from them. The block named like
+		// the SHARED pool keeps the default threshold: 128/256 is under it.
+		"nsx_ip_block/SHARED":  {allocated: 128, subnets: 2, blockSubnets: -1, threshold: 80, over: 0},
+		"nsx_ip_block/BLOCK-B": {allocated: 32, subnets: 1, blockSubnets: -1, threshold: 10, over: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("points = %v, want %v", got, want)
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
+func TestCollectIPPoolsBlockSaturates(t *testing.T) {
+	// Two /66 IPv6 subnets: 2^62 addresses each, one past MaxInt64 together.
+	const block = "/infra/ip-blocks/block-v6"
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/infra/ip-pools": `{"results":[{"id":"p1","display_name":"V6"}]}`,
+		"/policy/api/v1/infra/ip-pools/p1/ip-subnets": `{"results":[
+			{"id":"s1","resource_type":"IpAddressPoolBlockSubnet","ip_block_path":"` + block + `","size":"4611686018427387904"},
+			{"id":"s2","resource_type":"IpAddressPoolBlockSubnet","ip_block_path":"` + block + `","size":"4611686018427387904"}]}`,
+		"/policy/api/v1/infra/ip-blocks": `{"results":[
+			{"id":"block-v6","display_name":"BLOCK-V6","path":"` + block + `","cidr":"2001:db8::/64"}]}`,
+	}, nil)
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectIPPools:            true,
+		IPPoolThresholdPctDefault: 80,
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+	for _, p := range cc.collectIPPools(context.Background(), time.Now()) {
+		if p.Name() != "nsx_ip_block" {
+			continue
+		}
+		if got := pointField(p, "allocated"); got != math.MaxInt64 {
+			t.Fatalf("block allocated = %d, want MaxInt64 (saturated)", got)
+		}
+		return
+	}
+	t.Fatal("no nsx_ip_block point")
+}
@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/t1watch"
+	"nsx-collector/internal/telemetry"
+)
+
+// routeTables maps the nsx table names to the "table" tag.
+var routeTables = []struct{ name, tag string }{
+	{nsx.RoutingTable, "rib"},
+	{nsx.ForwardingTable, "fib"},
+}
+
+// collectRouteTables reads the RIB and FIB of every T0/VRF and returns one
+// nsx_route_table point per (gateway, edge node, table). Gateways are read
+// from a worker pool sized to the client's in-flight cap; a failed table is
+// counted as an error and skipped.
+func (cc *CapacityCollector) collectRouteTables(ctx context.Context, t0s []nsx.PolicyTier0, now time.Time) []*write.Point {
+	site := cc.site
+
+	// Edge node paths end with the transport node ID.
+	tnNames := map[string]string{}
+	if tns, err := cc.client.GetTransportNodes(ctx); err == nil {
+		for _, tn := range tns {
+			if tn.ID != "" && tn.DisplayName != "" {
+				tnNames[tn.ID] = tn.DisplayName
+			}
+		}
+	} else {
+		cc.logger.Debug("route tables: GetTransportNodes failed, names unavailable", zap.Error(err))
+	}
+	limit := t1watch.LimitResolver(cc.cfg.RouteLimitDefault, cc.cfg.RouteLimits)
+
+	var (
+		mu     sync.Mutex
+		points []*write.Point
+		wg     sync.WaitGroup
+	)
+	jobs := make(chan *nsx.PolicyTier0)
+	for w := 0; w < cc.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t0 := range jobs {
+				kind := "t0"
+				if t0.IsVRF() {
+					kind = "vrf"
+				}
+				for _, tbl := range routeTables {
+					edges, err := cc.client.GetRouteTable(ctx, t0.Path, tbl.name)
+					if err != nil {
+						// 404: gateway deleted, or a VRF/T0 without a service
+						// router (no edge cluster) — nothing to report.
+						if !nsx.IsNotFound(err) {
+							cc.logger.Debug("route table failed",
+								zap.String("gateway", t0.DisplayName),
+								zap.String("table", tbl.tag),
+								zap.Error(err),
+							)
+							telemetry.CollectErrors.WithLabelValues(site, "route_tables").Inc()
+						}
+						continue
+					}
+					for _, e := range edges {
+						byCat := map[string]int64{}
+						for _, r := range e.RouteEntries {
+							byCat[nsx.RouteCategory(r.RouteType)]++
+						}
+						total := int64(len(e.RouteEntries))
+						if e.Count > total {
+							total = e.Count
+						}
+						edgeID := nsx.LastPathSegment(e.EdgeNode)
+						p := influxpkg.RouteTablePoint(
+							site, kind, t0.DisplayName, t0.UniqueID, edgeID, tnNames[edgeID],
+							tbl.tag, byCat, total, limit(t0.DisplayName), now,
+						)
+						mu.Lock()
+						points = append(points, p)
+						mu.Unlock()
+					}
+				}
+			}
+		}()
+	}
+feed:
+	for i := range t0s {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- &t0s[i]:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	cc.logger.Debug("route tables collected", zap.Int("gateways", len(t0s)), zap.Int("points", len(points)))
+	return points
+}
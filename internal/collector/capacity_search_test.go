@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	"nsx-collector/internal/nsx"
+)
+
+var (
+	searchTestT0s = map[string]t0Meta{"/infra/tier-0s/vrf-a": {ID: "uuid-vrf-a", Name: "vrf-a", IsVRF: true}}
+	searchTestT1s = []nsx.PolicyTier1{{ID: "t1-app", UniqueID: "uuid-app", DisplayName: "t1-app", Path: "/infra/tier-1s/t1-app"}}
+)
+
+func TestSegmentsPerParent(t *testing.T) {
+	want := map[string]int64{"t1|t1-app|uuid-app": 2, "vrf|vrf-a|uuid-vrf-a": 1, "overlay|-|-": 1}
+	lists := map[string]string{
+		"/policy/api/v1/infra/segments": `{"results":[
+			{"id":"s1","connectivity_path":"/infra/tier-1s/t1-app"},
+			{"id":"s2","connectivity_path":"/infra/tier-1s/t1-app"},
+			{"id":"s3","connectivity_path":"/infra/tier-0s/vrf-a"},
+			{"id":"s4"}]}`,
+	}
+
+	t.Run("search", func(t *testing.T) {
+		c := newFakeNSX(t, map[string]string{
+			"/policy/api/v1/search/query": `{"results":[
+				{"parent_path":"/infra","connectivity_path":"/infra/tier-1s/t1-app"},
+				{"parent_path":"/infra","connectivity_path":"/infra/tier-1s/t1-app"},
+				{"parent_path":"/infra","connectivity_path":"/infra/tier-0s/vrf-a"},
+				{"parent_path":"/infra"},
+				{"parent_path":"/infra/tier-1s/t1-app","connectivity_path":"/infra/tier-1s/t1-app"}]}`,
+		}, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		})
+		cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{}, config.T1WatchConfig{}, nil, zap.NewNop())
+		got, err := cc.segmentsPerParent(context.Background(), searchTestT0s, searchTestT1s)
+		if err != nil {
+			t.Fatalf("segmentsPerParent: %v", err)
+		}
+		assertCounts(t, got, want)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		// search/query is not in bodies: 404 means the release lacks it.
+		c := newFakeNSX(t, lists, nil)
+		cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{}, config.T1WatchConfig{}, nil, zap.NewNop())
+		got, err := cc.segmentsPerParent(context.Background(), searchTestT0s, searchTestT1s)
+		if err != nil {
+			t.Fatalf("segmentsPerParent: %v", err)
+		}
+		assertCounts(t, got, want)
+		if c.Supports(nsx.CapPolicySearch) {
+			t.Errorf("policy search still marked supported after a 404")
+		}
+	})
+}
+
+func TestGWPoliciesPerGateway(t *testing.T) {
+	want := map[string]gwPolicyAcc{
+		"t1|t1-app|uuid-app":   {Rules: 7, Policies: 2},
+		"vrf|vrf-a|uuid-vrf-a": {Rules: 4, Policies: 1},
+	}
+
+	t.Run("aggregate", func(t *testing.T) {
+		c := newFakeNSX(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/policy/api/v1/search/aggregate" || r.Method != http.MethodPost {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var req nsx.AggregateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Primary.ResourceType != "GatewayPolicy" ||
+				len(req.Related) != 1 || req.Related[0].ResourceType != "Rule" {
+				t.Errorf("aggregate request = %+v (%v)", req, err)
+			}
+			w.Write([]byte(`{"results":[
+				{"primary":{"parent_path":"/infra/domains/default","scope":["/infra/tier-1s/t1-app","/infra/tier-0s/vrf-a"]},
+				 "related":[{"alias":"rules","count":4}]},
+				{"primary":{"parent_path":"/infra/domains/default","scope":["/infra/tier-1s/t1-app"]},
+				 "related":[{"alias":"rules","count":3}]},
+				{"primary":{"parent_path":"/infra/domains/other","scope":["/infra/tier-1s/t1-app"]},
+				 "related":[{"alias":"rules","count":9}]}]}`))
+		})
+		cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{}, config.T1WatchConfig{}, nil, zap.NewNop())
+		got, err := cc.gwPoliciesPerGateway(context.Background(), searchTestT0s, searchTestT1s)
+		if err != nil {
+			t.Fatalf("gwPoliciesPerGateway: %v", err)
+		}
+		assertCounts(t, got, want)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		c := newFakeNSX(t, map[string]string{
+			"/policy/api/v1/infra/domains/default/gateway-policies": `{"results":[
+				{"id":"p1","scope":["/infra/tier-1s/t1-app","/infra/tier-0s/vrf-a"],"rule_count":4},
+				{"id":"p2","scope":["/infra/tier-1s/t1-app"],"rule_count":3}]}`,
+		}, nil)
+		cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{}, config.T1WatchConfig{}, nil, zap.NewNop())
+		got, err := cc.gwPoliciesPerGateway(context.Background(), searchTestT0s, searchTestT1s)
+		if err != nil {
+			t.Fatalf("gwPoliciesPerGateway: %v", err)
+		}
+		assertCounts(t, got, want)
+		if c.Supports(nsx.CapSearchAggregate) {
+			t.Errorf("search aggregate still marked supported after a 404")
+		}
+	})
+}
+
+func assertCounts[V comparable](t *testing.T, got, want map[string]V) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("counts = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("counts = %v, want %v", got, want)
+		}
+	}
+}
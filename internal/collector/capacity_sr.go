@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/t1watch"
+	"nsx-collector/internal/telemetry"
+)
+
+// srCount is the SR tally of one edge node.
+type srCount struct {
+	t0, vrf, t1 int64
+	cluster     string // edge cluster NSX ID
+}
+
+func (s *srCount) total() int64 { return s.t0 + s.vrf + s.t1 }
+
+// collectServiceRouters counts service routers per edge node and per edge
+// cluster from the per_node_status of every router with an edge cluster
+// (routers without one are DR-only). Returns nsx_sr_per_edge and
+// nsx_sr_per_edge_cluster points for the capacity bucket. Routers are read
+// from a worker pool sized to the client's in-flight cap; a failed status
+// is counted as an error and its SRs are missing from this cycle.
+func (cc *CapacityCollector) collectServiceRouters(ctx context.Context, now time.Time) []*write.Point {
+	site := cc.site
+	routers, err := cc.client.GetLogicalRouters(ctx)
+	if err != nil {
+		cc.logger.Warn("sr per edge: list logical routers failed", zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(site, "sr_per_edge").Inc()
+		return nil
+	}
+	var withSR []nsx.LogicalRouter
+	for _, r := range routers {
+		if r.EdgeClusterID != "" {
+			withSR = append(withSR, r)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		edges = map[string]*srCount{}
+		wg    sync.WaitGroup
+	)
+	jobs := make(chan *nsx.LogicalRouter)
+	for w := 0; w < cc.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				st, err := cc.client.GetLogicalRouterStatus(ctx, r.ID)
+				if err != nil {
+					if !nsx.IsNotFound(err) {
+						cc.logger.Debug("sr per edge: router status failed", zap.String("router", r.DisplayName), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "sr_per_edge").Inc()
+					}
+					continue
+				}
+				mu.Lock()
+				for _, n := range st.PerNodeStatus {
+					if n.ServiceRouterID == "" || n.TransportNodeID == "" {
+						continue
+					}
+					e := edges[n.TransportNodeID]
+					if e == nil {
+						e = &srCount{cluster: r.EdgeClusterID}
+						edges[n.TransportNodeID] = e
+					}
+					switch r.RouterType {
+					case "TIER0":
+						e.t0++
+					case "VRF":
+						e.vrf++
+					default:
+						e.t1++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range withSR {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- &withSR[i]:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	tnNames := map[string]string{}
+	if tns, err := cc.client.GetTransportNodes(ctx); err == nil {
+		for _, tn := range tns {
+			tnNames[tn.ID] = tn.DisplayName
+		}
+	} else {
+		cc.logger.Debug("sr per edge: GetTransportNodes failed, names unavailable", zap.Error(err))
+	}
+	ecNames := map[string]string{}
+	if ecs, err := cc.client.GetPolicyEdgeClusters(ctx); err == nil {
+		for _, ec := range ecs {
+			if ec.NSXID != "" {
+				ecNames[ec.NSXID] = ec.DisplayName
+			}
+		}
+	} else {
+		cc.logger.Debug("sr per edge: policy edge-clusters failed, names unavailable", zap.Error(err))
+	}
+
+	edgeLimit := t1watch.LimitResolver(cc.cfg.SRLimitPerEdgeDefault, cc.cfg.SREdgeLimits)
+	type clusterAcc struct{ total, edges, maxPerEdge, limit int64 }
+	clusters := map[string]*clusterAcc{}
+	var points []*write.Point
+	for id, e := range edges {
+		name := tnNames[id]
+		cluster := ecNames[e.cluster]
+		if cluster == "" {
+			cluster = e.cluster
+		}
+		limit := edgeLimit(name)
+		points = append(points, influxpkg.ServiceRouterEdgePoint(site, id, name, cluster, e.t0, e.vrf, e.t1, limit, now))
+
+		c := clusters[cluster]
+		if c == nil {
+			c = &clusterAcc{}
+			clusters[cluster] = c
+		}
+		c.total += e.total()
+		c.edges++
+		c.limit += limit
+		if e.total() > c.maxPerEdge {
+			c.maxPerEdge = e.total()
+		}
+	}
+	for name, c := range clusters {
+		limit := c.limit
+		if v, ok := cc.cfg.SRClusterLimits[name]; ok && v > 0 {
+			limit = v
+		}
+		points = append(points, influxpkg.ServiceRouterClusterPoint(site, name, c.total, c.edges, c.maxPerEdge, limit, now))
+	}
+	cc.logger.Debug("sr per edge collected",
+		zap.Int("routers", len(withSR)),
+		zap.Int("edges", len(edges)),
+		zap.Int("clusters", len(clusters)),
+	)
+	return points
+}
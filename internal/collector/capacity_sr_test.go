@@ -0,0 +1,76 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+)
+
+func TestCollectServiceRouters(t *testing.T) {
+	status := map[string]string{
+		"t0": `{"per_node_status":[
+			{"transport_node_id":"e1","service_router_id":"sr-a","high_availability_status":"ACTIVE"},
+			{"transport_node_id":"e2","service_router_id":"sr-b","high_availability_status":"ACTIVE"}]}`,
+		"t1": `{"per_node_status":[
+			{"transport_node_id":"e1","service_router_id":"sr-c","high_availability_status":"ACTIVE"},
+			{"transport_node_id":"e2","service_router_id":"sr-d","high_availability_status":"STANDBY"}]}`,
+		"t1b": `{"per_node_status":[
+			{"transport_node_id":"e1","service_router_id":"sr-e","high_availability_status":"ACTIVE"}]}`,
+	}
+	bodies := map[string]string{
+		"/api/v1/logical-routers": `{"results":[
+			{"id":"t0","router_type":"TIER0","edge_cluster_id":"ec1"},
+			{"id":"t1","router_type":"TIER1","edge_cluster_id":"ec1"},
+			{"id":"t1b","router_type":"TIER1","edge_cluster_id":"ec1"},
+			{"id":"dr","router_type":"TIER1"}]}`,
+		"/api/v1/transport-nodes": `{"results":[{"id":"e1","display_name":"edge-01"},{"id":"e2","display_name":"edge-02"}]}`,
+		"/policy/api/v1/infra/sites/default/enforcement-points/default/edge-clusters": `{"results":[{"nsx_id":"ec1","display_name":"EC-PROD"}]}`,
+	}
+	for id, body := range status {
+		bodies["/api/v1/logical-routers/"+id+"/status"] = body
+	}
+	c := newFakeNSX(t, bodies, nil)
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{
+		CollectSRPerEdge:      true,
+		SRLimitPerEdgeDefault: 10,
+		SREdgeLimits:          map[string]int64{"edge-02": 4},
+	}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+	type row struct{ total, limit int64 }
+	got := map[string]row{}
+	for _, p := range cc.collectServiceRouters(context.Background(), time.Now()) {
+		key := p.Name()
+		for _, tag := range p.TagList() {
+			if tag.Key == "edge_name" || (p.Name() == "nsx_sr_per_edge_cluster" && tag.Key == "edge_cluster") {
+				key += "/" + tag.Value
+			}
+		}
+		var r row
+		for _, f := range p.FieldList() {
+			switch f.Key {
+			case "total":
+				r.total = f.Value.(int64)
+			case "limit":
+				r.limit = f.Value.(int64)
+			}
+		}
+		got[key] = r
+	}
+	want := map[string]row{
+		"nsx_sr_per_edge/edge-01":         {3, 10},
+		"nsx_sr_per_edge/edge-02":         {2, 4},
+		"nsx_sr_per_edge_cluster/EC-PROD": {5, 14},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("points = %v, want %v", got, want)
+	}
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("%s = %+v, want %+v", k, got[k], w)
+		}
+	}
+}
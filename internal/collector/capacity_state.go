@@ -0,0 +1,214 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/nsx"
+)
+
+// capacityState is the per-site state behind incremental Capacity NSX
+// collection, persisted in <state_dir>/capacity-<site>.json. It keeps the
+// last counts per T1 (NAT) and per parent (segments, gateway firewall) so a
+// cycle only re-queries what changed and re-emits the cached rest.
+//
+// Change signals:
+//   - a T1 that is new or whose _revision moved;
+//   - objects whose _last_modified_time is past Watermark, found with the
+//     Policy Search API (when the Manager supports it).
+//
+// Deletions of child objects (a NAT rule removed, a segment deleted) are not
+// visible to either signal, so a full resync runs every
+// capacity.full_resync_interval.
+type capacityState struct {
+	Site         string    `json:"site"`
+	Updated      time.Time `json:"updated"`
+	LastFullSync time.Time `json:"last_full_sync"`
+	// Watermark (epoch ms) is where the next cycle's change search starts.
+	Watermark int64 `json:"watermark"`
+	// T1s is keyed by T1 Policy path.
+	T1s map[string]t1CapacityState `json:"t1s"`
+	// Segments / GWPolicies are keyed by "kind|name|id"; nil = never fetched.
+	Segments   map[string]int64       `json:"segments,omitempty"`
+	GWPolicies map[string]gwPolicyAcc `json:"gw_policies,omitempty"`
+}
+
+// t1CapacityState is the cached view of one T1.
+type t1CapacityState struct {
+	Revision int64 `json:"revision"`
+	// NATRules is -1 until a count was obtained.
+	NATRules int `json:"nat_rules"`
+}
+
+// gwPolicyAcc is the gateway firewall tally of one gateway.
+type gwPolicyAcc struct {
+	Rules    int64 `json:"rules"`
+	Policies int64 `json:"policies"`
+}
+
+// changeWatermarkSkew is subtracted from the cycle start when advancing the
+// watermark, covering clock skew between collector and Manager. Re-checking
+// an object twice is harmless.
+const changeWatermarkSkew = 2 * time.Minute
+
+func (cc *CapacityCollector) statePath() string {
+	safeSite := strings.ToLower(strings.ReplaceAll(cc.site, "/", "_"))
+	return filepath.Join(cc.stateDir, "capacity-"+safeSite+".json")
+}
+
+func (cc *CapacityCollector) loadState() (*capacityState, error) {
+	fresh := &capacityState{Site: cc.site, T1s: map[string]t1CapacityState{}}
+	data, err := os.ReadFile(cc.statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return fresh, err
+	}
+	var st capacityState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fresh, err
+	}
+	if st.T1s == nil {
+		st.T1s = map[string]t1CapacityState{}
+	}
+	return &st, nil
+}
+
+func (cc *CapacityCollector) saveState(st *capacityState) error {
+	st.Updated = time.Unix(time.Now().Unix(), 0).UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cc.statePath(), data)
+}
+
+// loadIncremental returns the state to diff against (nil when
+// capacity.incremental is off) and whether this cycle must count in full:
+// no previous state, or the last full sync is older than
+// capacity.full_resync_interval.
+func (cc *CapacityCollector) loadIncremental(now time.Time) (*capacityState, bool) {
+	if cc.cfg.Incremental != nil && !*cc.cfg.Incremental {
+		return nil, true
+	}
+	if !cc.cfg.CollectSegments && !cc.cfg.CollectGWPolicies && !cc.cfg.CollectNATPerT1 {
+		return nil, true
+	}
+	st, err := cc.loadState()
+	if err != nil {
+		cc.logger.Warn("capacity load state failed, counting in full", zap.Error(err))
+	}
+	resync := cc.cfg.FullResyncInterval
+	if resync <= 0 {
+		resync = time.Hour
+	}
+	full := st.LastFullSync.IsZero() || now.Sub(st.LastFullSync) >= resync
+	return st, full
+}
+
+// diffT1s returns the T1 paths that are new or whose _revision moved since
+// st, and whether the T1 set changed at all (a T1 added, edited or
+// removed). A nil st (incremental off) counts as changed.
+func (st *capacityState) diffT1s(t1s []nsx.PolicyTier1) (map[string]bool, bool) {
+	changed := map[string]bool{}
+	if st == nil {
+		return changed, true
+	}
+	seen := make(map[string]struct{}, len(t1s))
+	for i := range t1s {
+		seen[t1s[i].Path] = struct{}{}
+		if prev, ok := st.T1s[t1s[i].Path]; !ok || prev.Revision != t1s[i].Revision {
+			changed[t1s[i].Path] = true
+		}
+	}
+	setChanged := len(changed) > 0
+	for p := range st.T1s {
+		if _, ok := seen[p]; !ok {
+			setChanged = true
+		}
+	}
+	return changed, setChanged
+}
+
+// unchangedSince reports whether search finds no object matching query
+// modified since the state watermark. Without search there is no such
+// signal, so it answers false and the caller refetches.
+func (cc *CapacityCollector) unchangedSince(ctx context.Context, st *capacityState, query string) bool {
+	if !cc.searchEnabled() || st.Watermark == 0 {
+		return false
+	}
+	n, err := cc.client.SearchCount(ctx, nsx.ModifiedSince(query, st.Watermark))
+	if err != nil {
+		if nsx.IsUnsupported(err) {
+			cc.client.MarkUnsupported(nsx.CapPolicySearch, err.Error())
+		}
+		cc.logger.Debug("change search failed, refetching", zap.String("query", query), zap.Error(err))
+		return false
+	}
+	return n == 0
+}
+
+// natIncremental recounts NAT rules only for T1s that changed — new or
+// moved _revision, a NAT rule modified since the watermark (search), or no
+// count from the previous cycle — and carries the cached count over for the
+// rest. Without search only the _revision signal applies; edits to NAT rules
+// on an untouched T1 then wait for the next full resync. Returns nil when
+// the change search fails or most T1s are stale, so the caller counts in
+// full.
+func (cc *CapacityCollector) natIncremental(ctx context.Context, st *capacityState, t1s []nsx.PolicyTier1, changed map[string]bool) map[string]int {
+	stale := make(map[string]bool, len(changed))
+	for p := range changed {
+		stale[p] = true
+	}
+	for p, prev := range st.T1s {
+		if prev.NATRules < 0 {
+			stale[p] = true
+		}
+	}
+	if cc.searchEnabled() {
+		hits, err := cc.client.CountByParent(ctx, nsx.ModifiedSince("resource_type:PolicyNatRule", st.Watermark), natRuleGateway)
+		switch {
+		case err == nil:
+			for p := range hits {
+				stale[p] = true
+			}
+		case nsx.IsUnsupported(err):
+			cc.client.MarkUnsupported(nsx.CapPolicySearch, err.Error())
+		default:
+			cc.logger.Warn("nat change search failed, counting in full", zap.Error(err))
+			return nil
+		}
+	}
+
+	var paths []string
+	for i := range t1s {
+		if stale[t1s[i].Path] {
+			paths = append(paths, t1s[i].Path)
+		}
+	}
+	if len(paths) > len(t1s)/2 {
+		// Mostly stale (e.g. collect_nat_per_t1 just turned on): one full
+		// count is cheaper than recounting T1 by T1.
+		return nil
+	}
+	fresh := cc.fanOutCount(ctx, "nat_per_t1", paths, cc.natRuleCount)
+	counts := make(map[string]int, len(t1s))
+	for i := range t1s {
+		p := t1s[i].Path
+		if n, ok := fresh[p]; ok {
+			counts[p] = n
+		} else if prev, ok := st.T1s[p]; ok && !stale[p] {
+			counts[p] = prev.NATRules
+		}
+	}
+	cc.logger.Debug("nat per t1 incremental",
+		zap.Int("t1s", len(t1s)), zap.Int("recounted", len(paths)), zap.Int("ok", len(fresh)))
+	return counts
+}
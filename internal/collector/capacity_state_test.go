@@ -0,0 +1,260 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	"nsx-collector/internal/nsx"
+)
+
+func TestLoadIncremental(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	off := false
+	cases := []struct {
+		name     string
+		cfg      config.CapacityConfig
+		lastFull time.Time // zero: no state file
+		corrupt  bool
+		wantNil  bool
+		wantFull bool
+	}{
+		{name: "no state", cfg: config.CapacityConfig{CollectNATPerT1: true}, wantFull: true},
+		{name: "recent full sync", cfg: config.CapacityConfig{CollectNATPerT1: true}, lastFull: now.Add(-10 * time.Minute)},
+		{name: "full resync expired", cfg: config.CapacityConfig{CollectNATPerT1: true}, lastFull: now.Add(-61 * time.Minute), wantFull: true},
+		{name: "custom resync interval", cfg: config.CapacityConfig{CollectNATPerT1: true, FullResyncInterval: 5 * time.Minute}, lastFull: now.Add(-10 * time.Minute), wantFull: true},
+		{name: "corrupt state", cfg: config.CapacityConfig{CollectNATPerT1: true}, corrupt: true, wantFull: true},
+		{name: "incremental off", cfg: config.CapacityConfig{CollectNATPerT1: true, Incremental: &off}, lastFull: now, wantNil: true, wantFull: true},
+		{name: "nothing incremental enabled", cfg: config.CapacityConfig{}, lastFull: now, wantNil: true, wantFull: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := NewCapacityCollector(testSite, nil, t.TempDir(), tc.cfg, config.T1WatchConfig{}, nil, zap.NewNop())
+			if !tc.lastFull.IsZero() {
+				if err := cc.saveState(&capacityState{Site: testSite, LastFullSync: tc.lastFull}); err != nil {
+					t.Fatalf("saveState: %v", err)
+				}
+			}
+			if tc.corrupt {
+				if err := os.WriteFile(cc.statePath(), []byte("{not json"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			st, full := cc.loadIncremental(now)
+			if (st == nil) != tc.wantNil || full != tc.wantFull {
+				t.Fatalf("loadIncremental = (nil %v, full %v), want (nil %v, full %v)", st == nil, full, tc.wantNil, tc.wantFull)
+			}
+			if st != nil && st.T1s == nil {
+				t.Fatalf("state without T1 map")
+			}
+		})
+	}
+}
+
+func TestDiffT1s(t *testing.T) {
+	prev := &capacityState{T1s: map[string]t1CapacityState{
+		"/infra/tier-1s/a": {Revision: 3},
+		"/infra/tier-1s/b": {Revision: 1},
+	}}
+	t1 := func(id string, rev int64) nsx.PolicyTier1 {
+		return nsx.PolicyTier1{Path: "/infra/tier-1s/" + id, Revision: rev}
+	}
+	cases := []struct {
+		name        string
+		st          *capacityState
+		t1s         []nsx.PolicyTier1
+		wantChanged []string
+		wantSet     bool
+	}{
+		{name: "unchanged", st: prev, t1s: []nsx.PolicyTier1{t1("a", 3), t1("b", 1)}},
+		{name: "revision bumped", st: prev, t1s: []nsx.PolicyTier1{t1("a", 4), t1("b", 1)}, wantChanged: []string{"/infra/tier-1s/a"}, wantSet: true},
+		{name: "T1 added", st: prev, t1s: []nsx.PolicyTier1{t1("a", 3), t1("b", 1), t1("c", 1)}, wantChanged: []string{"/infra/tier-1s/c"}, wantSet: true},
+		{name: "T1 removed", st: prev, t1s: []nsx.PolicyTier1{t1("a", 3)}, wantSet: true},
+		{name: "no state", st: nil, t1s: []nsx.PolicyTier1{t1("a", 3)}, wantSet: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			changed, setChanged := tc.st.diffT1s(tc.t1s)
+			var got []string
+			for p := range changed {
+				got = append(got, p)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tc.wantChanged, ",") || setChanged != tc.wantSet {
+				t.Fatalf("diffT1s = (%v, %v), want (%v, %v)", got, setChanged, tc.wantChanged, tc.wantSet)
+			}
+		})
+	}
+}
+
+func TestUnchangedSince(t *testing.T) {
+	noSearch := false
+	cases := []struct {
+		name      string
+		watermark int64
+		useSearch *bool
+		answer    string // result_count, or an HTTP status prefixed with "status "
+		want      bool
+		wantOff   bool // CapPolicySearch marked unsupported afterwards
+	}{
+		{name: "nothing modified", watermark: 1, answer: "0", want: true},
+		{name: "search hit after the watermark", watermark: 1, answer: "3"},
+		{name: "no watermark yet", watermark: 0, answer: "0"},
+		{name: "search disabled", watermark: 1, useSearch: &noSearch, answer: "0"},
+		{name: "search unsupported", watermark: 1, answer: "status 404", wantOff: true},
+		{name: "search failing", watermark: 1, answer: "status 500"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeNSX(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/policy/api/v1/search/query" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if code, ok := strings.CutPrefix(tc.answer, "status "); ok {
+					n, _ := strconv.Atoi(code)
+					w.WriteHeader(n)
+					return
+				}
+				w.Write([]byte(`{"result_count":` + tc.answer + `}`))
+			})
+			cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{UseSearch: tc.useSearch}, config.T1WatchConfig{}, nil, zap.NewNop())
+			st := &capacityState{Watermark: tc.watermark}
+			if got := cc.unchangedSince(context.Background(), st, "resource_type:Segment"); got != tc.want {
+				t.Fatalf("unchangedSince = %v, want %v", got, tc.want)
+			}
+			if off := !c.Supports(nsx.CapPolicySearch); off != tc.wantOff {
+				t.Fatalf("policy search off = %v, want %v", off, tc.wantOff)
+			}
+		})
+	}
+}
+
+func TestNATIncremental(t *testing.T) {
+	const watermark = 1_700_000_000_000
+	live := map[string]int{"a": 10, "b": 20, "c": 30, "d": 40}
+	cases := []struct {
+		name     string
+		changed  []string // T1 ids with a new/moved _revision
+		uncached []string // T1 ids without a count from the previous cycle
+		search   string   // NAT rule hits as T1 ids, or "status <code>"
+		want     map[string]int
+		wantOff  bool
+	}{
+		{
+			name: "nothing changed",
+			want: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4},
+		},
+		{
+			name:    "revision bumped",
+			changed: []string{"a"},
+			want:    map[string]int{"a": 10, "b": 2, "c": 3, "d": 4},
+		},
+		{
+			name:   "search hit after the watermark",
+			search: "b",
+			want:   map[string]int{"a": 1, "b": 20, "c": 3, "d": 4},
+		},
+		{
+			name:     "no previous count",
+			uncached: []string{"d"},
+			want:     map[string]int{"a": 1, "b": 2, "c": 3, "d": 40},
+		},
+		{
+			name:    "more than half stale",
+			changed: []string{"a", "b"},
+			search:  "c",
+			want:    nil,
+		},
+		{
+			name:    "search unsupported",
+			changed: []string{"a"},
+			search:  "status 404",
+			want:    map[string]int{"a": 10, "b": 2, "c": 3, "d": 4},
+			wantOff: true,
+		},
+		{
+			name:   "search failing",
+			search: "status 500",
+			want:   nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var recounted []string
+			c := newFakeNSX(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/policy/api/v1/search/query" {
+					want := nsx.ModifiedSince("resource_type:PolicyNatRule", watermark)
+					if r.URL.Query().Get("query") != want {
+						t.Errorf("search query = %q, want %q", r.URL.Query().Get("query"), want)
+					}
+					if code, ok := strings.CutPrefix(tc.search, "status "); ok {
+						n, _ := strconv.Atoi(code)
+						w.WriteHeader(n)
+						return
+					}
+					var hits []string
+					for _, id := range strings.Fields(tc.search) {
+						hits = append(hits, `{"parent_path":"/infra/tier-1s/`+id+`/nat/USER"}`)
+					}
+					w.Write([]byte(`{"results":[` + strings.Join(hits, ",") + `]}`))
+					return
+				}
+				id, ok := strings.CutPrefix(r.URL.Path, "/policy/api/v1/infra/tier-1s/")
+				if id, ok = strings.CutSuffix(id, "/nat/USER/nat-rules"); !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				mu.Lock()
+				recounted = append(recounted, id)
+				mu.Unlock()
+				w.Write([]byte(`{"result_count":` + strconv.Itoa(live[id]) + `}`))
+			})
+			cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{CollectNATPerT1: true}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+			st := &capacityState{Watermark: watermark, T1s: map[string]t1CapacityState{}}
+			var t1s []nsx.PolicyTier1
+			for i, id := range []string{"a", "b", "c", "d"} {
+				p := "/infra/tier-1s/" + id
+				t1s = append(t1s, nsx.PolicyTier1{Path: p})
+				st.T1s[p] = t1CapacityState{NATRules: i + 1}
+			}
+			for _, id := range tc.uncached {
+				st.T1s["/infra/tier-1s/"+id] = t1CapacityState{NATRules: -1}
+			}
+			changed := map[string]bool{}
+			for _, id := range tc.changed {
+				changed["/infra/tier-1s/"+id] = true
+			}
+
+			got := cc.natIncremental(context.Background(), st, t1s, changed)
+			if tc.want == nil {
+				if got != nil {
+					t.Fatalf("natIncremental = %v, want nil (full count)", got)
+				}
+				return
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("natIncremental = %v, want %v", got, tc.want)
+			}
+			for id, n := range tc.want {
+				if got["/infra/tier-1s/"+id] != n {
+					t.Errorf("%s = %d, want %d (recounted %v)", id, got["/infra/tier-1s/"+id], n, recounted)
+				}
+			}
+			if off := !c.Supports(nsx.CapPolicySearch); off != tc.wantOff {
+				t.Fatalf("policy search off = %v, want %v", off, tc.wantOff)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	"nsx-collector/internal/t1watch"
+)
+
+func TestConfirmMissingT1s(t *testing.T) {
+	// Policy ids differ from unique_id. "alive" still answers, "gone" is
+	// 404, "flaky" fails with a 500 and "legacy" predates policy_id in the
+	// snapshot, so it is checked on its logical router.
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/infra/tier-1s/t1-alive":            `{"id":"t1-alive","unique_id":"uuid-alive"}`,
+		"/api/v1/logical-routers/uuid-legacy-alive/status": `{"logical_router_id":"uuid-legacy-alive"}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/policy/api/v1/infra/tier-1s/t1-flaky" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if strings.Contains(r.URL.Path, "uuid-") && strings.HasPrefix(r.URL.Path, "/policy/") {
+			t.Errorf("Policy GET by unique_id: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	cc := NewCapacityCollector(testSite, c, t.TempDir(), config.CapacityConfig{}, config.T1WatchConfig{}, nil, zap.NewNop())
+
+	snap := &t1watch.Snapshot{Site: testSite, Known: map[string]t1watch.T1Info{
+		"uuid-listed":       {ID: "uuid-listed", PolicyID: "t1-listed"},
+		"uuid-alive":        {ID: "uuid-alive", PolicyID: "t1-alive"},
+		"uuid-gone":         {ID: "uuid-gone", PolicyID: "t1-gone"},
+		"uuid-flaky":        {ID: "uuid-flaky", PolicyID: "t1-flaky"},
+		"uuid-legacy-alive": {ID: "uuid-legacy-alive"},
+		"uuid-legacy-gone":  {ID: "uuid-legacy-gone"},
+	}}
+	live := cc.confirmMissingT1s(context.Background(), snap, []t1watch.LiveT1{{ID: "uuid-listed", PolicyID: "t1-listed"}})
+
+	var got []string
+	for _, t1 := range live {
+		got = append(got, t1.ID)
+	}
+	sort.Strings(got)
+	want := "uuid-alive,uuid-flaky,uuid-legacy-alive,uuid-listed"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("live after confirmation = %v, want %s", got, want)
+	}
+	for _, t1 := range live {
+		if t1.ID == "uuid-alive" && t1.PolicyID != "t1-alive" {
+			t.Errorf("carried-over T1 lost its Policy id: %+v", t1)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/t1watch"
+	"nsx-collector/internal/telemetry"
+)
+
+// CertificateCollector inventories every trust-management certificate on
+// the slow path: one nsx_certificate point per certificate with its days to
+// expiry, plus a Slack notice the first time a certificate crosses each
+// certificates.notify_days threshold and once more when it expires.
+// The severity follows the notice level (see certificateSeverity), one level
+// lower for self-signed certificates, as in the alarm reclassification.
+type CertificateCollector struct {
+	site     string
+	client   *nsx.Client
+	logger   *zap.Logger
+	stateDir string
+	// notifyDays is ascending, positive only.
+	notifyDays []int
+	// slack is nil when notices are off (no token/channel).
+	slack t1watch.SlackPoster
+}
+
+// certState is the per-site notice state, persisted in
+// <state_dir>/certificates-<site>.json so a restart does not repeat notices.
+type certState struct {
+	Site    string    `json:"site"`
+	Updated time.Time `json:"updated"`
+	// Notified is keyed by certificate ID.
+	Notified map[string]certNotice `json:"notified"`
+}
+
+// certNotice is the last notice posted for one certificate. A renewed
+// certificate (different not_after) starts over.
+type certNotice struct {
+	NotAfter int64 `json:"not_after"`
+	// Threshold is the smallest notify_days threshold noticed; -1 = expired.
+	Threshold int `json:"threshold"`
+}
+
+// NewCertificateCollector creates the certificate collector for one manager.
+// slack may be nil: points are still written, only notices are skipped.
+func NewCertificateCollector(site string, client *nsx.Client, stateDir string, notifyDays []int, slack t1watch.SlackPoster, logger *zap.Logger) *CertificateCollector {
+	var days []int
+	for _, d := range notifyDays {
+		if d > 0 {
+			days = append(days, d)
+		}
+	}
+	sort.Ints(days)
+	return &CertificateCollector{
+		site:       site,
+		client:     client,
+		logger:     logger,
+		stateDir:   stateDir,
+		notifyDays: days,
+		slack:      slack,
+	}
+}
+
+// Collect lists the certificates and returns their nsx_certificate points,
+// posting due notices along the way.
+func (cc *CertificateCollector) Collect(ctx context.Context, now time.Time) ([]*write.Point, error) {
+	site := cc.site
+	certs, err := cc.client.GetCertificates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var st *certState
+	if cc.slack != nil {
+		if st, err = cc.loadState(); err != nil {
+			cc.logger.Warn("certificates: load state failed, notices may repeat", zap.Error(err))
+		}
+	}
+
+	var (
+		points   []*write.Point
+		expiring int
+		posted   int
+	)
+	live := make(map[string]bool, len(certs))
+	for i := range certs {
+		c := &certs[i]
+		info, err := c.Info()
+		if err != nil {
+			cc.logger.Debug("certificates: undecodable certificate", zap.String("cert_id", c.ID), zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "certificates").Inc()
+			continue
+		}
+		live[c.ID] = true
+		days := int64(math.Floor(info.NotAfter.Sub(now).Hours() / 24))
+		level, due := cc.noticeLevel(days)
+		severity := "OK"
+		if due {
+			severity = certificateSeverity(level, info.SelfSigned)
+			expiring++
+		}
+		services := c.Services()
+		points = append(points, influxpkg.CertificatePoint(
+			site, c.ID, c.DisplayName, strings.Join(services, ","), info.Issuer,
+			info.SelfSigned, severity, info.NotAfter, days, now,
+		))
+
+		if !due || st == nil {
+			continue
+		}
+		prev, seen := st.Notified[c.ID]
+		if seen && prev.NotAfter == info.NotAfter.Unix() && prev.Threshold <= level {
+			continue
+		}
+		msg := formatCertificateNotice(site, c, services, info, days, severity)
+		if _, err := cc.slack.Post(msg); err != nil {
+			cc.logger.Warn("certificates: slack notice failed", zap.String("cert_name", c.DisplayName), zap.Error(err))
+			continue
+		}
+		st.Notified[c.ID] = certNotice{NotAfter: info.NotAfter.Unix(), Threshold: level}
+		telemetry.CertificateNotices.WithLabelValues(site).Inc()
+		posted++
+	}
+	telemetry.CertificatesExpiring.WithLabelValues(site).Set(float64(expiring))
+
+	if st != nil {
+		for id := range st.Notified {
+			if !live[id] {
+				delete(st.Notified, id)
+			}
+		}
+		if err := cc.saveState(st); err != nil {
+			cc.logger.Warn("certificates: save state failed", zap.Error(err))
+		}
+	}
+
+	cc.logger.Debug("certificates collected",
+		zap.Int("certificates", len(certs)),
+		zap.Int("expiring", expiring),
+		zap.Int("notices", posted),
+	)
+	return points, nil
+}
+
+// noticeLevel returns the threshold a certificate with days left falls in:
+// -1 once expired, else the smallest notify_days value >= days. due=false
+// when it is beyond every threshold.
+func (cc *CertificateCollector) noticeLevel(days int64) (level int, due bool) {
+	if days < 0 {
+		return -1, true
+	}
+	for _, t := range cc.notifyDays {
+		if days <= int64(t) {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// certificateSeverity maps a notice level to a severity: WARNING up to 60
+// days, HIGH within 30 and CRITICAL within 7 or once expired. Self-signed
+// internal certificates drop one level (CRITICAL → HIGH → WARNING → LOW).
+func certificateSeverity(level int, selfSigned bool) string {
+	sev := []string{"LOW", "WARNING", "HIGH", "CRITICAL"}
+	i := 1
+	switch {
+	case level <= 7:
+		i = 3
+	case level <= 30:
+		i = 2
+	}
+	if selfSigned {
+		i--
+	}
+	return sev[i]
+}
+
+// formatCertificateNotice builds the Slack text for one certificate.
+func formatCertificateNotice(site string, c *nsx.Certificate, services []string, info nsx.CertificateInfo, days int64, severity string) string {
+	name := c.DisplayName
+	if name == "" {
+		name = c.ID
+	}
+	usedBy := "sem uso"
+	if len(services) > 0 {
+		usedBy = strings.Join(services, ", ")
+	}
+	kind := "CA-signed"
+	if info.SelfSigned {
+		kind = "self-signed"
+	}
+	date := info.NotAfter.UTC().Format("2006-01-02")
+	if days < 0 {
+		return fmt.Sprintf("Certificado %s (%s) no %s EXPIROU em %s, ha %d dias — %s, emissor %s, severidade %s.",
+			name, usedBy, site, date, -days, kind, info.Issuer, severity)
+	}
+	return fmt.Sprintf("Certificado %s (%s) no %s expira em %d dias (%s) — %s, emissor %s, severidade %s.",
+		name, usedBy, site, days, date, kind, info.Issuer, severity)
+}
+
+func (cc *CertificateCollector) statePath() string {
+	safeSite := strings.ToLower(strings.ReplaceAll(cc.site, "/", "_"))
+	return filepath.Join(cc.stateDir, "certificates-"+safeSite+".json")
+}
+
+func (cc *CertificateCollector) loadState() (*certState, error) {
+	fresh := &certState{Site: cc.site, Notified: map[string]certNotice{}}
+	data, err := os.ReadFile(cc.statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return fresh, err
+	}
+	var st certState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fresh, err
+	}
+	if st.Notified == nil {
+		st.Notified = map[string]certNotice{}
+	}
+	return &st, nil
+}
+
+func (cc *CertificateCollector) saveState(st *certState) error {
+	st.Updated = time.Unix(time.Now().Unix(), 0).UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cc.statePath(), data)
+}
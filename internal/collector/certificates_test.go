@@ -0,0 +1,26 @@
+package collector
+
+import "testing"
+
+func TestCertificateSeverity(t *testing.T) {
+	cases := []struct {
+		level      int
+		selfSigned bool
+		want       string
+	}{
+		{60, false, "WARNING"},
+		{30, false, "HIGH"},
+		{7, false, "CRITICAL"},
+		{-1, false, "CRITICAL"},
+		{90, false, "WARNING"},
+		{60, true, "LOW"},
+		{30, true, "WARNING"},
+		{7, true, "HIGH"},
+		{-1, true, "HIGH"},
+	}
+	for _, tc := range cases {
+		if got := certificateSeverity(tc.level, tc.selfSigned); got != tc.want {
+			t.Errorf("certificateSeverity(%d, %v) = %s, want %s", tc.level, tc.selfSigned, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// clusterGroupTracker turns the detailed groups of /cluster/status into
+// per-group and per-member points, plus an event whenever a group changes
+// status between two cycles. Previous statuses live in memory only — the
+// first cycle after a restart baselines without events (same as BGP/HA).
+type clusterGroupTracker struct {
+	site   string
+	logger *zap.Logger
+
+	mu sync.Mutex
+	// prev[group_id] = group_status seen last cycle.
+	prev map[string]string
+}
+
+func newClusterGroupTracker(site string, logger *zap.Logger) *clusterGroupTracker {
+	return &clusterGroupTracker{site: site, logger: logger, prev: map[string]string{}}
+}
+
+// points returns nsx_cluster_group and nsx_cluster_member points for every
+// group, and nsx_cluster_group_event points for groups whose status moved.
+func (t *clusterGroupTracker) points(cs *nsx.ClusterStatus, now time.Time) []*write.Point {
+	site := t.site
+	groups := cs.DetailedClusterStatus.Groups
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var points []*write.Point
+	cur := make(map[string]string, len(groups))
+	for i := range groups {
+		g := &groups[i]
+		points = append(points, influxpkg.ClusterGroupPoint(site, g, now))
+		for j := range g.Members {
+			points = append(points, influxpkg.ClusterMemberPoint(site, g.GroupType, &g.Members[j], now))
+		}
+
+		status := strings.ToUpper(g.GroupStatus)
+		cur[g.GroupID] = status
+		old, seen := t.prev[g.GroupID]
+		if !seen || old == status {
+			continue
+		}
+		event := clusterGroupEvent(status)
+		if event == "recovered" {
+			t.logger.Info("cluster group recovered",
+				zap.String("group_type", g.GroupType),
+				zap.String("from", old),
+			)
+		} else {
+			t.logger.Warn("cluster group status changed",
+				zap.String("group_type", g.GroupType),
+				zap.String("from", old),
+				zap.String("to", status),
+				zap.Int("members_up", g.MembersUp()),
+				zap.Int("members_total", len(g.Members)),
+			)
+		}
+		points = append(points, influxpkg.ClusterGroupEventPoint(site, event, old, g, now))
+		telemetry.ClusterGroupEvents.WithLabelValues(site, g.GroupType, event).Inc()
+	}
+	// A response without groups (older release, partial answer) keeps the
+	// previous baseline instead of forgetting every group.
+	if len(groups) > 0 {
+		t.prev = cur
+	}
+	return points
+}
+
+// clusterGroupEvent names the transition into status.
+func clusterGroupEvent(status string) string {
+	switch status {
+	case "STABLE":
+		return "recovered"
+	case "DEGRADED":
+		return "degraded"
+	}
+	return "unavailable"
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/nsx"
+)
+
+func TestClusterGroupEvents(t *testing.T) {
+	tr := newClusterGroupTracker("TESP3", zap.NewNop())
+	status := func(ccp string) *nsx.ClusterStatus {
+		cs := &nsx.ClusterStatus{}
+		cs.DetailedClusterStatus.Groups = []nsx.ClusterGroup{
+			{GroupID: "g1", GroupType: "MANAGER", GroupStatus: "STABLE", Members: []nsx.ClusterGroupMember{
+				{MemberUUID: "m1", MemberStatus: "UP"}, {MemberUUID: "m2", MemberStatus: "UP"},
+			}},
+			{GroupID: "g2", GroupType: "CONTROLLER", GroupStatus: ccp, Members: []nsx.ClusterGroupMember{
+				{MemberUUID: "m1", MemberStatus: "UP"}, {MemberUUID: "m2", MemberStatus: "DOWN"},
+			}},
+		}
+		return cs
+	}
+	events := func(cs *nsx.ClusterStatus) []string {
+		var out []string
+		for _, p := range tr.points(cs, time.Now()) {
+			if p.Name() != "nsx_cluster_group_event" {
+				continue
+			}
+			for _, tag := range p.TagList() {
+				if tag.Key == "event" {
+					out = append(out, tag.Value)
+				}
+			}
+		}
+		return out
+	}
+
+	if got := events(status("STABLE")); len(got) != 0 {
+		t.Fatalf("baseline events = %v, want none", got)
+	}
+	if got := events(status("DEGRADED")); len(got) != 1 || got[0] != "degraded" {
+		t.Fatalf("degraded events = %v", got)
+	}
+	if got := events(status("DEGRADED")); len(got) != 0 {
+		t.Fatalf("steady events = %v, want none", got)
+	}
+	// A response without groups keeps the baseline.
+	if got := events(&nsx.ClusterStatus{}); len(got) != 0 {
+		t.Fatalf("empty response events = %v, want none", got)
+	}
+	if got := events(status("UNAVAILABLE")); len(got) != 1 || got[0] != "unavailable" {
+		t.Fatalf("unavailable events = %v", got)
+	}
+	if got := events(status("STABLE")); len(got) != 1 || got[0] != "recovered" {
+		t.Fatalf("recovered events = %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"nsx-collector/internal/nsx"
+)
+
+// testSite is the manager site the collector tests run against.
+const testSite = "TESP3"
+
+// newFakeNSX starts a fake Manager for one test and returns a client for it.
+// Each path in bodies is answered with its JSON body; any other path goes to
+// fallback when set, or gets a 404. The server is closed with the test.
+func newFakeNSX(t *testing.T, bodies map[string]string, fallback http.HandlerFunc) *nsx.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, ok := bodies[r.URL.Path]; ok {
+			w.Write([]byte(body))
+			return
+		}
+		if fallback != nil {
+			fallback(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := nsx.NewClient(nsx.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// pointTag returns the value of tag key on p, "" when absent.
+func pointTag(p *write.Point, key string) string {
+	for _, tg := range p.TagList() {
+		if tg.Key == key {
+			return tg.Value
+		}
+	}
+	return ""
+}
+
+// pointField returns the int64 field key of p, -1 when absent.
+func pointField(p *write.Point, key string) int64 {
+	for _, f := range p.FieldList() {
+		if f.Key == key {
+			if v, ok := f.Value.(int64); ok {
+				return v
+			}
+		}
+	}
+	return -1
+}
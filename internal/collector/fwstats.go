@@ -0,0 +1,403 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// FWRuleStatsCollector reads the rule statistics of every gateway firewall
+// policy and tracks, per rule and gateway, when its hit count last grew.
+// Each run returns one nsx_fw_rule_usage point per gateway for the capacity
+// bucket and rewrites fw-stale-rules-<site>.{csv,json} in
+// fw_rule_stats.export_dir with the rules unused for
+// fw_rule_stats.unused_days, for cleanup tickets. Hit history is persisted
+// in <state_dir>/fw-rule-stats-<site>.json, since the NSX counters are
+// cumulative and carry no "last hit" timestamp.
+type FWRuleStatsCollector struct {
+	site     string
+	client   *nsx.Client
+	logger   *zap.Logger
+	stateDir string
+	cfg      config.FWRuleStatsConfig
+
+	mu sync.Mutex
+}
+
+// fwRuleStatsState is the per-site hit history.
+type fwRuleStatsState struct {
+	Site    string    `json:"site"`
+	Updated time.Time `json:"updated"`
+	// Rules is keyed by "<gateway path>|<rule path>".
+	Rules map[string]*fwRuleState `json:"rules"`
+}
+
+// fwRuleState is the history of one rule on one gateway.
+type fwRuleState struct {
+	GatewayKind    string    `json:"gateway_kind"`
+	GatewayName    string    `json:"gateway_name"`
+	GatewayID      string    `json:"gateway_id"`
+	PolicyID       string    `json:"policy_id"`
+	PolicyName     string    `json:"policy_name"`
+	RulePath       string    `json:"rule_path"`
+	InternalRuleID string    `json:"internal_rule_id"`
+	HitCount       int64     `json:"hit_count"`
+	FirstSeen      time.Time `json:"first_seen"`
+	// LastHit is the run in which the hit count last grew; zero = never
+	// since FirstSeen.
+	LastHit time.Time `json:"last_hit,omitempty"`
+}
+
+// hit reports whether cur is new traffic against the previous count. A
+// counter that went down was reset (republish, edge reboot); any non-zero
+// value after a reset is counted as a hit.
+func (r *fwRuleState) hit(cur int64) bool {
+	return cur > r.HitCount || (cur < r.HitCount && cur > 0)
+}
+
+// unusedFor returns how long the rule has gone without a hit, measured from
+// its last hit or, failing that, from when it was first seen.
+func (r *fwRuleState) unusedFor(now time.Time) time.Duration {
+	if !r.LastHit.IsZero() {
+		return now.Sub(r.LastHit)
+	}
+	return now.Sub(r.FirstSeen)
+}
+
+// staleRule is one row of the stale-rule export.
+type staleRule struct {
+	Site           string `json:"site"`
+	GatewayKind    string `json:"gateway_kind"`
+	GatewayName    string `json:"gateway_name"`
+	GatewayID      string `json:"gateway_id"`
+	PolicyID       string `json:"policy_id"`
+	PolicyName     string `json:"policy_name"`
+	RuleID         string `json:"rule_id"`
+	InternalRuleID string `json:"internal_rule_id"`
+	RulePath       string `json:"rule_path"`
+	HitCount       int64  `json:"hit_count"`
+	FirstSeen      string `json:"first_seen"`
+	LastHit        string `json:"last_hit"`
+	DaysUnused     int64  `json:"days_unused"`
+}
+
+// NewFWRuleStatsCollector creates the rule statistics collector for one
+// manager. stateDir holds the hit history (t1_watch.state_dir).
+func NewFWRuleStatsCollector(site string, client *nsx.Client, stateDir string, cfg config.FWRuleStatsConfig, logger *zap.Logger) *FWRuleStatsCollector {
+	return &FWRuleStatsCollector{
+		site:     site,
+		client:   client,
+		logger:   logger,
+		stateDir: stateDir,
+		cfg:      cfg,
+	}
+}
+
+// Interval is how often the worker runs Collect.
+func (f *FWRuleStatsCollector) Interval() time.Duration { return f.cfg.Interval }
+
+// Collect reads the statistics of every gateway policy, updates the hit
+// history and returns the nsx_fw_rule_usage points. Policies whose
+// statistics could not be read keep their history untouched and are left
+// out of this run's counts.
+func (f *FWRuleStatsCollector) Collect(ctx context.Context, now time.Time) ([]*write.Point, error) {
+	site := f.site
+	policies, err := f.client.GetGatewayPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fw rule stats: %w", err)
+	}
+	t0ByPath := map[string]t0Meta{}
+	if t0s, err := f.client.GetPolicyTier0s(ctx); err == nil {
+		for i := range t0s {
+			t := &t0s[i]
+			t0ByPath[t.Path] = t0Meta{ID: t.UniqueID, Name: t.DisplayName, IsVRF: t.IsVRF()}
+		}
+	} else {
+		f.logger.Debug("fw rule stats: GetPolicyTier0s failed, names unavailable", zap.Error(err))
+	}
+	t1s, err := f.client.GetPolicyTier1s(ctx)
+	if err != nil {
+		f.logger.Debug("fw rule stats: GetPolicyTier1s failed, names unavailable", zap.Error(err))
+	}
+
+	type result struct {
+		stats []nsx.RuleStatistics
+		ok    bool
+	}
+	results := make([]result, len(policies))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < f.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				stats, err := f.client.GetGatewayPolicyStatistics(ctx, policies[i].ID)
+				if err != nil {
+					if nsx.IsNotFound(err) {
+						// Deleted between the list and the read.
+						continue
+					}
+					f.logger.Debug("fw rule stats failed", zap.String("policy", policies[i].DisplayName), zap.Error(err))
+					telemetry.CollectErrors.WithLabelValues(site, "fw_rule_stats").Inc()
+					continue
+				}
+				results[i] = result{stats: stats, ok: true}
+			}
+		}()
+	}
+feed:
+	for i := range policies {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	st, err := f.loadState()
+	if err != nil {
+		f.logger.Warn("fw rule stats: load state failed, hit history restarts", zap.Error(err))
+	}
+
+	type gwAcc struct {
+		kind, name, id                        string
+		rules, unused, hits, pkts, bytes, ses int64
+	}
+	gateways := map[string]*gwAcc{}
+	seen := map[string]bool{}
+	readOK := map[string]bool{}
+	unusedAfter := time.Duration(f.cfg.UnusedDays) * 24 * time.Hour
+	var stale []staleRule
+	for i, r := range results {
+		if !r.ok {
+			continue
+		}
+		p := &policies[i]
+		readOK[p.ID] = true
+		for _, s := range r.stats {
+			if s.Rule == "" {
+				continue
+			}
+			gwPaths := p.Scope
+			if s.LRPath != "" {
+				gwPaths = []string{s.LRPath}
+			}
+			for _, gwPath := range gwPaths {
+				kind, name, id := classifyConnectivityPath(gwPath, t0ByPath, t1s)
+				if kind == "overlay" || kind == "unknown" {
+					continue
+				}
+				key := gwPath + "|" + s.Rule
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				rs := st.Rules[key]
+				if rs == nil {
+					rs = &fwRuleState{FirstSeen: now, HitCount: s.HitCount}
+					st.Rules[key] = rs
+				} else if rs.hit(s.HitCount) {
+					rs.LastHit = now
+				}
+				rs.HitCount = s.HitCount
+				rs.GatewayKind, rs.GatewayName, rs.GatewayID = kind, name, id
+				rs.PolicyID, rs.PolicyName = p.ID, p.DisplayName
+				rs.RulePath, rs.InternalRuleID = s.Rule, s.InternalRuleID
+
+				g := gateways[gwPath]
+				if g == nil {
+					g = &gwAcc{kind: kind, name: name, id: id}
+					gateways[gwPath] = g
+				}
+				g.rules++
+				g.hits += s.HitCount
+				g.pkts += s.PacketCount
+				g.bytes += s.ByteCount
+				g.ses += s.SessionCount
+				if idle := rs.unusedFor(now); idle >= unusedAfter {
+					g.unused++
+					stale = append(stale, newStaleRule(site, rs, idle))
+				}
+			}
+		}
+	}
+
+	// Forget rules that are gone: deleted policies, and rules no longer
+	// reported by a policy read this run.
+	live := make(map[string]bool, len(policies))
+	for i := range policies {
+		live[policies[i].ID] = true
+	}
+	for key, rs := range st.Rules {
+		if !live[rs.PolicyID] || (readOK[rs.PolicyID] && !seen[key]) {
+			delete(st.Rules, key)
+		}
+	}
+	if err := f.saveState(st); err != nil {
+		f.logger.Warn("fw rule stats: save state failed", zap.Error(err))
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		a, b := stale[i], stale[j]
+		if a.GatewayName != b.GatewayName {
+			return a.GatewayName < b.GatewayName
+		}
+		if a.DaysUnused != b.DaysUnused {
+			return a.DaysUnused > b.DaysUnused
+		}
+		return a.RulePath < b.RulePath
+	})
+	if err := f.export(stale); err != nil {
+		f.logger.Warn("fw rule stats: export failed", zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(site, "fw_rule_stats_export").Inc()
+	}
+
+	points := make([]*write.Point, 0, len(gateways))
+	for _, g := range gateways {
+		points = append(points, influxpkg.FWRuleUsagePoint(
+			site, g.kind, g.name, g.id, g.rules, g.unused, g.hits, g.pkts, g.bytes, g.ses, now,
+		))
+	}
+	telemetry.FWUnusedRules.WithLabelValues(site).Set(float64(len(stale)))
+	f.logger.Debug("fw rule stats collected",
+		zap.Int("policies", len(policies)),
+		zap.Int("policies_read", len(readOK)),
+		zap.Int("rules", len(seen)),
+		zap.Int("unused", len(stale)),
+	)
+	return points, nil
+}
+
+func newStaleRule(site string, rs *fwRuleState, idle time.Duration) staleRule {
+	lastHit := ""
+	if !rs.LastHit.IsZero() {
+		lastHit = rs.LastHit.UTC().Format(time.RFC3339)
+	}
+	return staleRule{
+		Site:           site,
+		GatewayKind:    rs.GatewayKind,
+		GatewayName:    rs.GatewayName,
+		GatewayID:      rs.GatewayID,
+		PolicyID:       rs.PolicyID,
+		PolicyName:     rs.PolicyName,
+		RuleID:         nsx.LastPathSegment(rs.RulePath),
+		InternalRuleID: rs.InternalRuleID,
+		RulePath:       rs.RulePath,
+		HitCount:       rs.HitCount,
+		FirstSeen:      rs.FirstSeen.UTC().Format(time.RFC3339),
+		LastHit:        lastHit,
+		DaysUnused:     int64(idle.Hours() / 24),
+	}
+}
+
+// staleRuleColumns is the CSV header, in the order of staleRule.
+var staleRuleColumns = []string{
+	"site", "gateway_kind", "gateway_name", "gateway_id", "policy_id", "policy_name",
+	"rule_id", "internal_rule_id", "rule_path", "hit_count", "first_seen", "last_hit", "days_unused",
+}
+
+// export rewrites the stale-rule files for every configured format. An
+// empty list still rewrites the files, so resolved rules disappear.
+func (f *FWRuleStatsCollector) export(rows []staleRule) error {
+	base := filepath.Join(f.cfg.ExportDir, "fw-stale-rules-"+f.safeSite())
+	for _, format := range f.cfg.ExportFormats {
+		var data []byte
+		switch strings.ToLower(format) {
+		case "csv":
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf)
+			_ = w.Write(staleRuleColumns)
+			for _, r := range rows {
+				_ = w.Write([]string{
+					r.Site, r.GatewayKind, r.GatewayName, r.GatewayID, r.PolicyID, r.PolicyName,
+					r.RuleID, r.InternalRuleID, r.RulePath, strconv.FormatInt(r.HitCount, 10),
+					r.FirstSeen, r.LastHit, strconv.FormatInt(r.DaysUnused, 10),
+				})
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return err
+			}
+			data = buf.Bytes()
+		case "json":
+			if rows == nil {
+				rows = []staleRule{}
+			}
+			var err error
+			if data, err = json.MarshalIndent(rows, "", "  "); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown export format %q", format)
+		}
+		if err := writeFileAtomic(base+"."+strings.ToLower(format), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FWRuleStatsCollector) safeSite() string {
+	return strings.ToLower(strings.ReplaceAll(f.site, "/", "_"))
+}
+
+func (f *FWRuleStatsCollector) statePath() string {
+	return filepath.Join(f.stateDir, "fw-rule-stats-"+f.safeSite()+".json")
+}
+
+func (f *FWRuleStatsCollector) loadState() (*fwRuleStatsState, error) {
+	fresh := &fwRuleStatsState{Site: f.site, Rules: map[string]*fwRuleState{}}
+	data, err := os.ReadFile(f.statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return fresh, err
+	}
+	var st fwRuleStatsState
+	if err := json.Unmarshal(data, &st); err != nil {
+		// Keep the corrupt file for inspection; the fresh state saved at the
+		// end of the run would overwrite it.
+		if aside, rerr := setAside(f.statePath()); rerr == nil {
+			err = fmt.Errorf("%w (moved to %s)", err, aside)
+		}
+		return fresh, err
+	}
+	if st.Rules == nil {
+		st.Rules = map[string]*fwRuleState{}
+	}
+	return &st, nil
+}
+
+func (f *FWRuleStatsCollector) saveState(st *fwRuleStatsState) error {
+	st.Updated = time.Unix(time.Now().Unix(), 0).UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.statePath(), data)
+}
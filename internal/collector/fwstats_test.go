@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	"nsx-collector/internal/nsx"
+)
+
+func TestFWRuleStatsUnusedRules(t *testing.T) {
+	hits := map[string]int64{"r1": 5, "r2": 0}
+	c := newFakeNSX(t, map[string]string{
+		"/policy/api/v1/infra/domains/default/gateway-policies": `{"results":[{"id":"p1","display_name":"Edge-Policy","scope":["/infra/tier-1s/t1-app"],"rule_count":2}]}`,
+		"/policy/api/v1/infra/tier-0s":                          `{"results":[]}`,
+		"/policy/api/v1/infra/tier-1s":                          `{"results":[{"id":"t1-app","path":"/infra/tier-1s/t1-app","display_name":"T1-APP","unique_id":"u-t1"}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy/api/v1/infra/domains/default/gateway-policies/p1/statistics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		stats := []nsx.RuleStatistics{
+			{Rule: "/infra/domains/default/gateway-policies/p1/rules/r1", LRPath: "/infra/tier-1s/t1-app", InternalRuleID: "1001", HitCount: hits["r1"]},
+			{Rule: "/infra/domains/default/gateway-policies/p1/rules/r2", LRPath: "/infra/tier-1s/t1-app", InternalRuleID: "1002", HitCount: hits["r2"]},
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"results": []interface{}{map[string]interface{}{"statistics": map[string]interface{}{"results": stats}}},
+		})
+		w.Write(body)
+	})
+	dir := t.TempDir()
+	f := NewFWRuleStatsCollector(testSite, c, dir, config.FWRuleStatsConfig{
+		UnusedDays:    30,
+		ExportDir:     dir,
+		ExportFormats: []string{"csv", "json"},
+	}, zap.NewNop())
+
+	unused := func(now time.Time) int64 {
+		t.Helper()
+		points, err := f.Collect(context.Background(), now)
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		if len(points) != 1 {
+			t.Fatalf("points = %d, want 1", len(points))
+		}
+		return pointField(points[0], "unused")
+	}
+
+	start := time.Unix(1_700_000_000, 0)
+	day := 24 * time.Hour
+	if n := unused(start); n != 0 {
+		t.Fatalf("first run unused = %d, want 0 (not observed long enough)", n)
+	}
+	hits["r1"] = 9
+	if n := unused(start.Add(20 * day)); n != 0 {
+		t.Fatalf("day 20 unused = %d, want 0", n)
+	}
+	// r2 never hit since first seen; r1 last hit on day 20.
+	if n := unused(start.Add(31 * day)); n != 1 {
+		t.Fatalf("day 31 unused = %d, want 1", n)
+	}
+	// A counter reset followed by traffic counts as a hit: only r2 is idle.
+	hits["r1"] = 2
+	if n := unused(start.Add(51 * day)); n != 1 {
+		t.Fatalf("day 51 unused = %d, want 1", n)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "fw-stale-rules-tesp3.json"))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	var rows []staleRule
+	if err := json.Unmarshal(data, &rows); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if len(rows) != 1 || rows[0].RuleID != "r2" || rows[0].GatewayName != "T1-APP" || rows[0].DaysUnused != 51 {
+		t.Fatalf("export = %+v", rows)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fw-stale-rules-tesp3.csv")); err != nil {
+		t.Fatalf("csv export: %v", err)
+	}
+}
+
+func TestFWRuleStatsCorruptStateSetAside(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFWRuleStatsCollector(testSite, nil, dir, config.FWRuleStatsConfig{}, zap.NewNop())
+	if err := os.WriteFile(f.statePath(), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st, err := f.loadState()
+	if err == nil || st == nil || len(st.Rules) != 0 {
+		t.Fatalf("loadState = (%v, %v), want a fresh state and an error", st, err)
+	}
+	if _, err := os.Stat(f.statePath()); !os.IsNotExist(err) {
+		t.Fatalf("corrupt state still in place: %v", err)
+	}
+	aside, _ := filepath.Glob(f.statePath() + ".corrupt-*")
+	if len(aside) != 1 {
+		t.Fatalf("set-aside files = %v, want 1", aside)
+	}
+	if data, _ := os.ReadFile(aside[0]); string(data) != "{not json" {
+		t.Fatalf("set-aside content = %q", data)
+	}
+}
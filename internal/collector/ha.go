@@ -34,7 +34,8 @@ type haClusterInventory struct {
 	T0ClusterID string         `json:"t0_cluster_id"`   // NSX edge_cluster_id
 	T0Name      string         `json:"t0_name"`         // first T0 display name attached to this cluster
 	Observed    []haObservedT1 `json:"observed"`        // current watchlist (size <= ha_watch.size)
-	// MissCount[T1_ID] = consecutive cycles where the T1 was 404/missing.
+	// MissCount[T1_ID] = consecutive cycles where the T1 was 404/missing
+	// (other errors — 5xx, timeouts, 429 — never count as a miss).
 	// We only substitute after 2 consecutive misses (configured below).
 	MissCount map[string]int `json:"miss_count,omitempty"`
 }
@@ -153,13 +154,13 @@ func (h *HACollector) CollectHA(ctx context.Context) ([]*write.Point, error) {
 		}
 	}
 
-	// 3. Fetch HA status for each observed T1 — parallel with a small semaphore.
+	// 3. Fetch HA status for each observed T1 in parallel. Concurrency and
+	// request rate are bounded by the client's shared limiter.
 	now := time.Now()
 	var (
 		ptsMu   sync.Mutex
 		points  []*write.Point
 		wg      sync.WaitGroup
-		sem     = make(chan struct{}, 4)
 		missedT1 = map[string]map[string]struct{}{} // cluster → T1 ids that 404'd this cycle
 		missedMu sync.Mutex
 		// activeByCluster[clusterID][t1_id] = transport_node_id currently ACTIVE
@@ -175,20 +176,20 @@ func (h *HACollector) CollectHA(ctx context.Context) ([]*write.Point, error) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				sem <- struct{}{}
-				defer func() { <-sem }()
 
 				st, err := h.client.GetLogicalRouterStatus(ctx, obs.ID)
 				if err != nil {
-					// Treat as "missing" for healing accounting; we don't know
-					// for sure it's a 404 (could be 5xx), but the rate-limit
-					// path already retries. A persistent failure → substitute.
-					missedMu.Lock()
-					if missedT1[clusterID] == nil {
-						missedT1[clusterID] = map[string]struct{}{}
+					// Only a real 404 counts as "missing" for healing; a 5xx,
+					// timeout or throttled request says nothing about the T1
+					// and must not get it substituted.
+					if nsx.IsNotFound(err) {
+						missedMu.Lock()
+						if missedT1[clusterID] == nil {
+							missedT1[clusterID] = map[string]struct{}{}
+						}
+						missedT1[clusterID][obs.ID] = struct{}{}
+						missedMu.Unlock()
 					}
-					missedT1[clusterID][obs.ID] = struct{}{}
-					missedMu.Unlock()
 					h.logger.Debug("ha: status fetch failed",
 						zap.String("t1_id", obs.ID),
 						zap.String("t1_name", obs.Name),
@@ -230,7 +231,7 @@ func (h *HACollector) CollectHA(ctx context.Context) ([]*write.Point, error) {
 		seen := activeByCluster[clusterID]
 		if len(seen) == 0 {
 			// Whole cluster failed (manager unreachable, all 404). Skip — next
-			// cycle will retry. Update miss counters for substitution.
+			// cycle will retry. Update miss counters for substitution (404s only).
 			h.bumpMissCounts(ci, missedT1[clusterID])
 			continue
 		}
@@ -446,7 +447,7 @@ func (h *HACollector) refreshWatchlist(ci *haClusterInventory, t1s []nsx.Logical
 	}
 }
 
-// bumpMissCounts increments the miss counter for T1s that failed this cycle.
+// bumpMissCounts increments the miss counter for T1s that returned 404 this cycle.
 // Substitution happens lazily on the next refreshWatchlist call.
 func (h *HACollector) bumpMissCounts(ci *haClusterInventory, missed map[string]struct{}) {
 	if len(missed) == 0 {
@@ -493,12 +494,8 @@ func shuffleT1s(s []nsx.LogicalRouter) {
 // ---------------------------------------------------------------------------
 
 func (h *HACollector) inventoryPath() string {
-	dir := h.manager.StateDir
-	if dir == "" {
-		dir = "/home/nsx_collector/state"
-	}
 	safeSite := strings.ToLower(strings.ReplaceAll(h.manager.Site, "/", "_"))
-	return filepath.Join(dir, "ha-watch-"+safeSite+".json")
+	return filepath.Join(h.manager.StateDir, "ha-watch-"+safeSite+".json")
 }
 
 func (h *HACollector) loadInventory() (*HAInventory, error) {
@@ -518,20 +515,11 @@ func (h *HACollector) loadInventory() (*HAInventory, error) {
 }
 
 func (h *HACollector) saveInventory(inv *HAInventory) error {
-	path := h.inventoryPath()
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("mkdir state_dir: %w", err)
-	}
 	data, err := json.MarshalIndent(inv, "", "  ")
 	if err != nil {
 		return err
 	}
-	// Atomic write: tmp + rename
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
-		return err
-	}
-	return os.Rename(tmp, path)
+	return writeFileAtomic(h.inventoryPath(), data)
 }
 
 // ---------------------------------------------------------------------------
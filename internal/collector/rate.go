@@ -1,10 +1,14 @@
 package collector
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
 )
 
 type counterState struct {
@@ -12,20 +16,22 @@ type counterState struct {
 	ts    time.Time
 }
 
-// RateResult holds the calculated rate for a single interface.
+// RateResult holds the calculated rates for a single interface.
 type RateResult struct {
 	RxBps           float64
 	TxBps           float64
 	RxUtilizationPct float64
 	TxUtilizationPct float64
 	LinkSpeedMbps   int64
+	// Packet, drop and error rates (per second) plus drop percentage.
+	influxpkg.PacketRates
 }
 
 // RateCalculator computes per-interface bandwidth rates from cumulative byte counters.
 // It stores the previous counter values in memory and calculates the delta on each call.
 type RateCalculator struct {
 	mu    sync.Mutex
-	state map[string]counterState // key: "node:iface:rx" or "node:iface:tx"
+	state map[string]counterState // key: "node:iface:<counter>" (see ifaceCounters)
 }
 
 func NewRateCalculator() *RateCalculator {
@@ -34,34 +40,58 @@ func NewRateCalculator() *RateCalculator {
 	}
 }
 
-// Calculate computes rx/tx rates in bits per second from cumulative byte counters.
+// Sanity caps: a rate above these means a counter reset read as a wrap.
+const (
+	maxBytesPerSec   = 100_000_000_000 / 8 // 100 Gbps
+	maxPacketsPerSec = 200_000_000
+)
+
+// ifaceCounters lists the cumulative counters kept per interface, in the
+// order Calculate reads them.
+var ifaceCounters = [...]string{"rx", "tx", "rx_packets", "tx_packets", "rx_dropped", "tx_dropped", "rx_errors", "tx_errors"}
+
+// Calculate computes rx/tx rates in bits per second, packets per second,
+// drops/sec and errors/sec from cumulative interface counters.
 // Returns nil on the first sample for a given interface (needs two readings).
-// Handles counter wraps for uint64 and discards samples where the computed rate
-// exceeds a sanity threshold (counter reset).
-func (rc *RateCalculator) Calculate(nodeName, ifaceID string, rxBytes, txBytes uint64, linkSpeedMbps int64, now time.Time) *RateResult {
+// Handles counter wraps for uint64 and discards samples where any computed
+// rate exceeds a sanity threshold (counter reset), so packet/drop/error rates
+// share the byte rates' wrap/stale/reset protection.
+func (rc *RateCalculator) Calculate(nodeName, ifaceID string, stats *nsx.InterfaceStats, linkSpeedMbps int64, now time.Time) *RateResult {
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 
-	rxKey := nodeName + ":" + ifaceID + ":rx"
-	txKey := nodeName + ":" + ifaceID + ":tx"
-
-	prevRx, hasRx := rc.state[rxKey]
-	prevTx, hasTx := rc.state[txKey]
-
-	rc.state[rxKey] = counterState{value: rxBytes, ts: now}
-	rc.state[txKey] = counterState{value: txBytes, ts: now}
-
-	if !hasRx || !hasTx {
+	cur := [len(ifaceCounters)]uint64{
+		uint64(stats.RxBytes), uint64(stats.TxBytes),
+		uint64(stats.RxPackets), uint64(stats.TxPackets),
+		uint64(stats.RxDropped), uint64(stats.TxDropped),
+		uint64(stats.RxErrors), uint64(stats.TxErrors),
+	}
+	var prev [len(ifaceCounters)]counterState
+	complete := true
+	for i, name := range ifaceCounters {
+		key := nodeName + ":" + ifaceID + ":" + name
+		p, ok := rc.state[key]
+		complete = complete && ok
+		prev[i] = p
+		rc.state[key] = counterState{value: cur[i], ts: now}
+	}
+	if !complete {
 		return nil
 	}
 
-	elapsed := now.Sub(prevRx.ts).Seconds()
+	elapsed := now.Sub(prev[0].ts).Seconds()
 	if elapsed < 10 {
 		return nil
 	}
 
-	rxBytesPerSec := counterRate(prevRx.value, rxBytes, elapsed)
-	txBytesPerSec := counterRate(prevTx.value, txBytes, elapsed)
+	var perSec [len(ifaceCounters)]float64
+	for i := range cur {
+		perSec[i] = counterRate(prev[i].value, cur[i], elapsed)
+		if perSec[i] < 0 {
+			return nil
+		}
+	}
+	rxBytesPerSec, txBytesPerSec := perSec[0], perSec[1]
 
 	// Stale read: NSX Manager occasionally returns the same counter twice
 	// in a row (internal cache). On a link with known speed and >30s window,
@@ -71,15 +101,15 @@ func (rc *RateCalculator) Calculate(nodeName, ifaceID string, rxBytes, txBytes u
 		return nil
 	}
 
-	if rxBytesPerSec < 0 || txBytesPerSec < 0 {
-		return nil
-	}
-
 	// Sanity check: discard if rate exceeds 100 Gbps (likely counter reset)
-	const maxBytesPerSec = 100_000_000_000 / 8
 	if rxBytesPerSec > maxBytesPerSec || txBytesPerSec > maxBytesPerSec {
 		return nil
 	}
+	for _, r := range perSec[2:] {
+		if r > maxPacketsPerSec {
+			return nil
+		}
+	}
 
 	rxBps := rxBytesPerSec * 8
 	txBps := txBytesPerSec * 8
@@ -88,6 +118,16 @@ func (rc *RateCalculator) Calculate(nodeName, ifaceID string, rxBytes, txBytes u
 		RxBps:         rxBps,
 		TxBps:         txBps,
 		LinkSpeedMbps: linkSpeedMbps,
+		PacketRates: influxpkg.PacketRates{
+			RxPps:          perSec[2],
+			TxPps:          perSec[3],
+			RxDropsPerSec:  perSec[4],
+			TxDropsPerSec:  perSec[5],
+			RxErrorsPerSec: perSec[6],
+			TxErrorsPerSec: perSec[7],
+			RxDropPct:      dropPct(perSec[4], perSec[2]),
+			TxDropPct:      dropPct(perSec[5], perSec[3]),
+		},
 	}
 
 	if linkSpeedMbps > 0 {
@@ -99,8 +139,8 @@ func (rc *RateCalculator) Calculate(nodeName, ifaceID string, rxBytes, txBytes u
 	if result.RxUtilizationPct > 30 || result.TxUtilizationPct > 30 {
 		zap.L().Warn("rate debug",
 			zap.String("key", nodeName+":"+ifaceID),
-			zap.Uint64("delta_rx", rxBytes-prevRx.value),
-			zap.Uint64("delta_tx", txBytes-prevTx.value),
+			zap.Uint64("delta_rx", cur[0]-prev[0].value),
+			zap.Uint64("delta_tx", cur[1]-prev[1].value),
 			zap.Float64("elapsed_sec", elapsed),
 			zap.Float64("rx_bytes_sec", rxBytesPerSec),
 			zap.Float64("tx_bytes_sec", txBytesPerSec),
@@ -115,6 +155,50 @@ func (rc *RateCalculator) Calculate(nodeName, ifaceID string, rxBytes, txBytes u
 	return result
 }
 
+// dropPct is the share of packets dropped: drops / (delivered + drops).
+func dropPct(dropsPerSec, pps float64) float64 {
+	if total := dropsPerSec + pps; total > 0 {
+		return dropsPerSec / total * 100
+	}
+	return 0
+}
+
+// CounterRate returns the per-second rate of one cumulative counter stored
+// under key, with the same protections as Calculate: nil-equivalent (ok=false)
+// on the first sample, on a window under 10s, and when the rate exceeds
+// maxPerSec (counter reset read as a wrap).
+func (rc *RateCalculator) CounterRate(key string, value uint64, maxPerSec float64, now time.Time) (float64, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	prev, ok := rc.state[key]
+	rc.state[key] = counterState{value: value, ts: now}
+	if !ok {
+		return 0, false
+	}
+	elapsed := now.Sub(prev.ts).Seconds()
+	if elapsed < 10 {
+		return 0, false
+	}
+	rate := counterRate(prev.value, value, elapsed)
+	if rate < 0 || rate > maxPerSec {
+		return 0, false
+	}
+	return rate, true
+}
+
+// Forget drops every counter stored under a key starting with prefix, for
+// objects that no longer exist (a deleted T1).
+func (rc *RateCalculator) Forget(prefix string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key := range rc.state {
+		if strings.HasPrefix(key, prefix) {
+			delete(rc.state, key)
+		}
+	}
+}
+
 // counterRate calculates bytes/sec handling uint64 counter wrap.
 func counterRate(prev, curr uint64, elapsedSec float64) float64 {
 	var delta uint64
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"nsx-collector/internal/nsx"
+)
+
+func TestCalculatePacketRates(t *testing.T) {
+	rc := NewRateCalculator()
+	t0 := time.Unix(1_700_000_000, 0)
+	first := &nsx.InterfaceStats{RxBytes: 1000, TxBytes: 1000, RxPackets: 100, TxPackets: 100}
+	if r := rc.Calculate("edge1", "fp-eth0", first, 10000, t0); r != nil {
+		t.Fatalf("first sample = %+v, want nil", r)
+	}
+
+	second := &nsx.InterfaceStats{
+		RxBytes: 1000 + 60*1250, TxBytes: 1000 + 60*2500,
+		RxPackets: 100 + 60*90, TxPackets: 100 + 60*50,
+		RxDropped: 60 * 10, RxErrors: 60 * 2,
+	}
+	r := rc.Calculate("edge1", "fp-eth0", second, 10000, t0.Add(time.Minute))
+	if r == nil {
+		t.Fatal("second sample = nil")
+	}
+	if r.RxBps != 10000 || r.TxBps != 20000 {
+		t.Fatalf("bps = %v/%v, want 10000/20000", r.RxBps, r.TxBps)
+	}
+	if r.RxPps != 90 || r.TxPps != 50 {
+		t.Fatalf("pps = %v/%v, want 90/50", r.RxPps, r.TxPps)
+	}
+	if r.RxDropsPerSec != 10 || r.RxErrorsPerSec != 2 || r.TxDropsPerSec != 0 {
+		t.Fatalf("drops/errors = %+v", r.PacketRates)
+	}
+	if math.Abs(r.RxDropPct-10) > 1e-9 {
+		t.Fatalf("rx drop pct = %v, want 10", r.RxDropPct)
+	}
+}
+
+func TestCalculateDiscardsErrorCounterReset(t *testing.T) {
+	rc := NewRateCalculator()
+	t0 := time.Unix(1_700_000_000, 0)
+	rc.Calculate("edge1", "fp-eth0", &nsx.InterfaceStats{RxBytes: 1000, TxBytes: 1000, RxErrors: 500}, 0, t0)
+	// Error counter went backwards (datapath restart): read as a wrap, the
+	// rate is implausible and the whole sample is dropped.
+	r := rc.Calculate("edge1", "fp-eth0", &nsx.InterfaceStats{RxBytes: 2000, TxBytes: 2000, RxErrors: 3}, 0, t0.Add(time.Minute))
+	if r != nil {
+		t.Fatalf("sample after reset = %+v, want nil", r)
+	}
+}
+
+func TestCalculateDiscardsStaleRead(t *testing.T) {
+	rc := NewRateCalculator()
+	t0 := time.Unix(1_700_000_000, 0)
+	s := &nsx.InterfaceStats{RxBytes: 1000, TxBytes: 1000, RxPackets: 10, TxPackets: 10}
+	rc.Calculate("edge1", "fp-eth0", s, 25000, t0)
+	if r := rc.Calculate("edge1", "fp-eth0", s, 25000, t0.Add(time.Minute)); r != nil {
+		t.Fatalf("stale sample = %+v, want nil", r)
+	}
+}
@@ -0,0 +1,313 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// realizedObject is one object whose realization state is tracked.
+type realizedObject struct {
+	kind   string // transport_node | t0 | vrf | t1 | segment
+	id     string
+	name   string
+	key    string // Policy path, or the transport node ID
+	parent string // VRF/T0 display name, "" when none
+}
+
+// RealizationCollector tracks realization failures: every run it reads the
+// configuration state of all transport nodes and the Policy realized status
+// of a rotating sample of T0/VRF, T1 and segments (realization.sample_size,
+// plus every object last seen in ERROR or IN_PROGRESS so recoveries show up
+// on the next run). It writes nsx_realization_summary per object type and
+// parent VRF/T0, and nsx_realization_event when an object enters or leaves
+// ERROR; an object first seen in ERROR is an event too. States live in
+// memory only.
+type RealizationCollector struct {
+	site   string
+	client *nsx.Client
+	logger *zap.Logger
+	cfg    config.RealizationConfig
+
+	mu sync.Mutex
+	// prev[key] = last normalized state read.
+	prev map[string]string
+	// after is the key of the last Policy object sampled.
+	after string
+}
+
+// NewRealizationCollector creates the realization collector for one manager.
+func NewRealizationCollector(site string, client *nsx.Client, cfg config.RealizationConfig, logger *zap.Logger) *RealizationCollector {
+	return &RealizationCollector{
+		site:   site,
+		client: client,
+		logger: logger,
+		cfg:    cfg,
+		prev:   map[string]string{},
+	}
+}
+
+// Interval is how often the worker runs Collect.
+func (r *RealizationCollector) Interval() time.Duration { return r.cfg.Interval }
+
+// Collect reads the transport node states and the sampled Policy realized
+// statuses and returns the summary and event points.
+func (r *RealizationCollector) Collect(ctx context.Context) ([]*write.Point, error) {
+	site := r.site
+	now := time.Now()
+	objects, err := r.policyObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type reading struct {
+		obj     realizedObject
+		state   string
+		message string
+	}
+	var readings []reading
+
+	// Transport nodes: one paginated list covers all of them.
+	var tnObjects []realizedObject
+	if states, err := r.client.GetTransportNodeStates(ctx); err != nil {
+		r.logger.Warn("realization: transport node states failed", zap.Error(err))
+		telemetry.CollectErrors.WithLabelValues(site, "realization").Inc()
+	} else {
+		names := map[string]string{}
+		if tns, err := r.client.GetTransportNodes(ctx); err == nil {
+			for _, tn := range tns {
+				names[tn.ID] = tn.DisplayName
+			}
+		} else {
+			r.logger.Debug("realization: GetTransportNodes failed, names unavailable", zap.Error(err))
+		}
+		for i := range states {
+			s := &states[i]
+			if s.TransportNodeID == "" {
+				continue
+			}
+			o := realizedObject{kind: "transport_node", id: s.TransportNodeID, name: names[s.TransportNodeID], key: s.TransportNodeID}
+			tnObjects = append(tnObjects, o)
+			readings = append(readings, reading{obj: o, state: s.Status(), message: s.Message()})
+		}
+	}
+
+	// Policy objects: previously failing ones plus the next rotating slice.
+	batch := r.nextBatch(objects)
+	results := make([]string, len(batch))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < r.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				st, err := r.client.GetRealizedStatus(ctx, batch[i].key)
+				if err != nil {
+					if !nsx.IsNotFound(err) {
+						r.logger.Debug("realized status failed", zap.String("path", batch[i].key), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "realization").Inc()
+					}
+					continue
+				}
+				results[i] = st.Status()
+			}
+		}()
+	}
+feed:
+	for i := range batch {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for i, state := range results {
+		if state != "" {
+			readings = append(readings, reading{obj: batch[i], state: state})
+		}
+	}
+
+	var points []*write.Point
+	var events int
+	for _, rd := range readings {
+		old, seen := r.prev[rd.obj.key]
+		r.prev[rd.obj.key] = rd.state
+		failed := rd.state == nsx.RealizationError
+		if seen && failed == (old == nsx.RealizationError) {
+			continue
+		}
+		if !seen && !failed {
+			continue
+		}
+		event := "failed"
+		if !failed {
+			event = "recovered"
+		}
+		points = append(points, influxpkg.RealizationEventPoint(
+			site, rd.obj.kind, rd.obj.id, rd.obj.name, rd.obj.parent, event, old, rd.state, rd.message, now,
+		))
+		telemetry.RealizationEvents.WithLabelValues(site, rd.obj.kind, event).Inc()
+		events++
+	}
+
+	// Summaries over the whole inventory, from the last state of each
+	// object. A failed transport node list keeps the previous states.
+	type sumKey struct{ kind, parent string }
+	type sumAcc struct{ objects, known, errors, inProgress int64 }
+	sums := map[sumKey]*sumAcc{}
+	errorsByKind := map[string]int64{}
+	live := map[string]bool{}
+	all := append(objects, tnObjects...)
+	for _, o := range all {
+		live[o.key] = true
+		k := sumKey{o.kind, o.parent}
+		a := sums[k]
+		if a == nil {
+			a = &sumAcc{}
+			sums[k] = a
+		}
+		a.objects++
+		state, known := r.prev[o.key]
+		if !known {
+			continue
+		}
+		a.known++
+		switch state {
+		case nsx.RealizationError:
+			a.errors++
+			errorsByKind[o.kind]++
+		case nsx.RealizationInProgress:
+			a.inProgress++
+		}
+	}
+	if tnObjects == nil {
+		// Transport node list unavailable this run: keep their states.
+		for key := range r.prev {
+			if !live[key] && !isPolicyPath(key) {
+				live[key] = true
+			}
+		}
+	}
+	for key := range r.prev {
+		if !live[key] {
+			delete(r.prev, key)
+		}
+	}
+	for k, a := range sums {
+		points = append(points, influxpkg.RealizationSummaryPoint(site, k.kind, k.parent, a.objects, a.known, a.errors, a.inProgress, now))
+	}
+	for _, kind := range []string{"transport_node", "t0", "vrf", "t1", "segment"} {
+		telemetry.RealizationErrors.WithLabelValues(site, kind).Set(float64(errorsByKind[kind]))
+	}
+
+	r.logger.Debug("realization collected",
+		zap.Int("transport_nodes", len(tnObjects)),
+		zap.Int("policy_objects", len(objects)),
+		zap.Int("sampled", len(batch)),
+		zap.Int("events", events),
+	)
+	return points, nil
+}
+
+// policyObjects lists the T0/VRF, T1 and segments, sorted by path, with
+// the VRF/T0 each one hangs from.
+func (r *RealizationCollector) policyObjects(ctx context.Context) ([]realizedObject, error) {
+	t0s, err := r.client.GetPolicyTier0s(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("realization: %w", err)
+	}
+	t1s, err := r.client.GetPolicyTier1s(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("realization: %w", err)
+	}
+	segments, err := r.client.GetPolicySegments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("realization: %w", err)
+	}
+
+	t0ByPath := make(map[string]t0Meta, len(t0s))
+	var objects []realizedObject
+	for i := range t0s {
+		t := &t0s[i]
+		t0ByPath[t.Path] = t0Meta{ID: t.UniqueID, Name: t.DisplayName, IsVRF: t.IsVRF()}
+		kind, parent := "t0", ""
+		if t.IsVRF() {
+			kind, parent = "vrf", t.DisplayName
+		}
+		objects = append(objects, realizedObject{kind: kind, id: t.ID, name: t.DisplayName, key: t.Path, parent: parent})
+	}
+	t1Parent := make(map[string]string, len(t1s))
+	for i := range t1s {
+		t := &t1s[i]
+		parent := t0ByPath[t.Tier0Path].Name
+		t1Parent[t.Path] = parent
+		objects = append(objects, realizedObject{kind: "t1", id: t.ID, name: t.DisplayName, key: t.Path, parent: parent})
+	}
+	for i := range segments {
+		s := &segments[i]
+		if s.Path == "" {
+			continue
+		}
+		parent := ""
+		switch kind, name, _ := classifyConnectivityPath(s.ConnectivityPath, t0ByPath, t1s); kind {
+		case "t1":
+			parent = t1Parent[s.ConnectivityPath]
+		case "t0", "vrf":
+			parent = name
+		}
+		objects = append(objects, realizedObject{kind: "segment", id: s.ID, name: s.DisplayName, key: s.Path, parent: parent})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].key < objects[j].key })
+	return objects, nil
+}
+
+// nextBatch returns the Policy objects to read this run: every object last
+// seen in ERROR or IN_PROGRESS plus the next realization.sample_size
+// objects after r.after, wrapping around once. objects is sorted by key.
+func (r *RealizationCollector) nextBatch(objects []realizedObject) []realizedObject {
+	taken := map[string]bool{}
+	var batch []realizedObject
+	for _, o := range objects {
+		if s := r.prev[o.key]; s == nsx.RealizationError || s == nsx.RealizationInProgress {
+			batch = append(batch, o)
+			taken[o.key] = true
+		}
+	}
+	n := r.cfg.SampleSize
+	if n <= 0 || n > len(objects) {
+		n = len(objects)
+	}
+	if n == 0 {
+		return batch
+	}
+	start := sort.Search(len(objects), func(i int) bool { return objects[i].key > r.after })
+	for i := 0; i < n; i++ {
+		o := objects[(start+i)%len(objects)]
+		if !taken[o.key] {
+			batch = append(batch, o)
+			taken[o.key] = true
+		}
+		r.after = o.key
+	}
+	return batch
+}
+
+// isPolicyPath reports whether key is a Policy path rather than a
+// transport node ID.
+func isPolicyPath(key string) bool { return len(key) > 0 && key[0] == '/' }
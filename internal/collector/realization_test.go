@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+)
+
+func TestRealizationEvents(t *testing.T) {
+	var mu sync.Mutex
+	status := map[string]string{
+		"/infra/segments/s1":  "SUCCESS",
+		"/infra/segments/s2":  "SUCCESS",
+		"/infra/tier-0s/vrf1": "SUCCESS",
+		"/infra/tier-1s/t1a":  "ERROR",
+	}
+	reads := map[string]int{}
+	c := newFakeNSX(t, map[string]string{
+		"/api/v1/transport-nodes/state": `{"results":[{"transport_node_id":"tn-1","state":"failed","failure_message":"host switch config failed"}]}`,
+		"/api/v1/transport-nodes":       `{"results":[{"id":"tn-1","display_name":"esx-01"}]}`,
+		"/policy/api/v1/infra/tier-0s":  `{"results":[{"id":"vrf1","display_name":"VRF-A","path":"/infra/tier-0s/vrf1","vrf_config":{"tier0_path":"/infra/tier-0s/t0"}}]}`,
+		"/policy/api/v1/infra/tier-1s":  `{"results":[{"id":"t1a","display_name":"T1-A","path":"/infra/tier-1s/t1a","tier0_path":"/infra/tier-0s/vrf1"}]}`,
+		"/policy/api/v1/infra/segments": `{"results":[
+			{"id":"s1","display_name":"SEG-1","path":"/infra/segments/s1","connectivity_path":"/infra/tier-1s/t1a"},
+			{"id":"s2","display_name":"SEG-2","path":"/infra/segments/s2"}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy/api/v1/infra/realized-state/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		p := r.URL.Query().Get("intent_path")
+		mu.Lock()
+		reads[p]++
+		st := status[p]
+		mu.Unlock()
+		w.Write([]byte(`{"intent_path":"` + p + `","consolidated_status":{"consolidated_status":"` + st + `"}}`))
+	})
+	rc := NewRealizationCollector(testSite, c, config.RealizationConfig{SampleSize: 2}, zap.NewNop())
+
+	events := func(points []*write.Point) map[string]string {
+		got := map[string]string{}
+		for _, p := range points {
+			if p.Name() == "nsx_realization_event" {
+				got[pointTag(p, "object_name")] = pointTag(p, "event")
+			}
+		}
+		return got
+	}
+	collect := func() []*write.Point {
+		t.Helper()
+		points, err := rc.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		return points
+	}
+
+	// Run 1 samples the two segments; the failed transport node is an event.
+	if got := events(collect()); len(got) != 1 || got["esx-01"] != "failed" {
+		t.Fatalf("run 1 events = %v", got)
+	}
+	// Run 2 samples VRF-A and T1-A, first seen in ERROR.
+	points := collect()
+	if got := events(points); len(got) != 1 || got["T1-A"] != "failed" {
+		t.Fatalf("run 2 events = %v", got)
+	}
+	var t1Errors int64 = -1
+	for _, p := range points {
+		if p.Name() == "nsx_realization_summary" && pointTag(p, "object_type") == "t1" && pointTag(p, "parent") == "VRF-A" {
+			t1Errors = pointField(p, "error")
+		}
+	}
+	if t1Errors != 1 {
+		t.Fatalf("t1 errors under VRF-A = %d, want 1", t1Errors)
+	}
+
+	// Run 3 wraps to the segments but still re-reads the failing T1.
+	mu.Lock()
+	status["/infra/tier-1s/t1a"] = "SUCCESS"
+	mu.Unlock()
+	if got := events(collect()); len(got) != 1 || got["T1-A"] != "recovered" {
+		t.Fatalf("run 3 events = %v", got)
+	}
+	if reads["/infra/tier-1s/t1a"] != 2 || reads["/infra/segments/s1"] != 2 {
+		t.Fatalf("reads = %v", reads)
+	}
+}
@@ -0,0 +1,32 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State files live in t1_watch.state_dir (config default
+// /home/nsx_collector/state; ha-watch uses the manager's state_dir) as one
+// JSON document per collector and site.
+
+// writeFileAtomic writes data to path via tmp + rename, creating the
+// directory if needed.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// setAside renames an unreadable state file to <path>.corrupt-<unix> so the
+// next save does not destroy it, and returns the new name.
+func setAside(path string) (string, error) {
+	aside := fmt.Sprintf("%s.corrupt-%d", path, time.Now().Unix())
+	return aside, os.Rename(path, aside)
+}
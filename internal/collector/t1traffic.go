@@ -0,0 +1,306 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// T1TrafficCollector computes per-T1 (per-customer) traffic from the T1 side
+// of each T1→T0 link port, so a saturated edge uplink can be traced back to
+// the tenants behind it. Each polled T1 costs one statistics request; the
+// sampling mode (config.T1TrafficConfig.Mode) bounds how many are polled per
+// run. Rates need two readings of the same T1, so a T1 first shows up on its
+// second visit.
+type T1TrafficCollector struct {
+	site     string
+	client   *nsx.Client
+	logger   *zap.Logger
+	rateCalc *RateCalculator
+	cfg      config.T1TrafficConfig
+
+	mu sync.Mutex
+	// cursor is the rotating position into the sorted candidate list.
+	cursor int
+	// lastBps[t1_id] = rx+tx bps at its last visit — the top_n ranking.
+	lastBps map[string]float64
+	// polled holds the T1s with counters in rateCalc, so deleted T1s can
+	// be forgotten there too.
+	polled map[string]struct{}
+}
+
+// NewT1TrafficCollector creates the per-T1 traffic collector for one manager.
+func NewT1TrafficCollector(site string, client *nsx.Client, rateCalc *RateCalculator, cfg config.T1TrafficConfig, logger *zap.Logger) *T1TrafficCollector {
+	switch cfg.Mode {
+	case "all", "rotating", "top_n":
+	default:
+		logger.Warn("t1_traffic: unknown mode, using top_n", zap.String("mode", cfg.Mode))
+		cfg.Mode = "top_n"
+	}
+	return &T1TrafficCollector{
+		site:     site,
+		client:   client,
+		logger:   logger,
+		rateCalc: rateCalc,
+		cfg:      cfg,
+		lastBps:  map[string]float64{},
+		polled:   map[string]struct{}{},
+	}
+}
+
+// Interval is how often the worker runs Collect.
+func (t *T1TrafficCollector) Interval() time.Duration { return t.cfg.Interval }
+
+// t1Uplink is one candidate: a T1 with its link port and resolved tags.
+type t1Uplink struct {
+	id, name, portID           string
+	parentT0, vrf, edgeCluster string
+}
+
+// Collect polls the selected T1 uplink ports and returns nsx_t1_bandwidth
+// points for those with two readings.
+func (t *T1TrafficCollector) Collect(ctx context.Context) ([]*write.Point, error) {
+	site := t.site
+	cands, err := t.candidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	picked := t.pick(cands)
+	telemetry.T1TrafficSampled.WithLabelValues(site).Set(float64(len(picked)))
+
+	var (
+		mu     sync.Mutex
+		points []*write.Point
+		rates  = map[string]float64{}
+		read   []string
+		wg     sync.WaitGroup
+	)
+	jobs := make(chan *t1Uplink)
+	for w := 0; w < t.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				st, err := t.client.GetLogicalRouterPortStats(ctx, u.portID)
+				if err != nil {
+					if !nsx.IsNotFound(err) {
+						t.logger.Debug("t1 traffic: port stats failed", zap.String("t1_name", u.name), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "t1_traffic").Inc()
+					}
+					continue
+				}
+				var rxB, txB, rxP, txP uint64
+				for _, n := range st.PerNodeStatistics {
+					rxB += uint64(n.Rx.TotalBytes)
+					txB += uint64(n.Tx.TotalBytes)
+					rxP += uint64(n.Rx.TotalPackets)
+					txP += uint64(n.Tx.TotalPackets)
+				}
+				now := time.Now()
+				key := t.rateKey(u.id)
+				mu.Lock()
+				read = append(read, u.id)
+				mu.Unlock()
+				rxBps, ok1 := t.rateCalc.CounterRate(key+"rx_bytes", rxB, maxBytesPerSec, now)
+				txBps, ok2 := t.rateCalc.CounterRate(key+"tx_bytes", txB, maxBytesPerSec, now)
+				rxPps, ok3 := t.rateCalc.CounterRate(key+"rx_packets", rxP, maxPacketsPerSec, now)
+				txPps, ok4 := t.rateCalc.CounterRate(key+"tx_packets", txP, maxPacketsPerSec, now)
+				if !ok1 || !ok2 || !ok3 || !ok4 {
+					continue
+				}
+				p := influxpkg.T1BandwidthPoint(site, u.id, u.name, u.parentT0, u.vrf, u.edgeCluster,
+					rxBps*8, txBps*8, rxPps, txPps, now)
+				mu.Lock()
+				points = append(points, p)
+				rates[u.id] = (rxBps + txBps) * 8
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, u := range picked {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- u:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	t.mu.Lock()
+	live := make(map[string]struct{}, len(cands))
+	for _, u := range cands {
+		live[u.id] = struct{}{}
+	}
+	for id := range t.lastBps {
+		if _, ok := live[id]; !ok {
+			delete(t.lastBps, id)
+		}
+	}
+	for _, id := range read {
+		t.polled[id] = struct{}{}
+	}
+	for id := range t.polled {
+		if _, ok := live[id]; !ok {
+			t.rateCalc.Forget(t.rateKey(id))
+			delete(t.polled, id)
+		}
+	}
+	for id, bps := range rates {
+		t.lastBps[id] = bps
+	}
+	t.mu.Unlock()
+
+	t.logger.Debug("t1 traffic collected",
+		zap.String("mode", t.cfg.Mode),
+		zap.Int("candidates", len(cands)),
+		zap.Int("polled", len(picked)),
+		zap.Int("points", len(points)),
+	)
+	return points, nil
+}
+
+// rateKey is the prefix of the rateCalc counters of one T1.
+func (t *T1TrafficCollector) rateKey(t1ID string) string {
+	return t.site + ":t1:" + t1ID + ":"
+}
+
+// candidates lists every T1 with a T1→T0 link port, sorted by ID so the
+// rotating cursor walks a stable order. Parent/VRF/edge cluster names come
+// from the Policy inventory (best-effort).
+func (t *T1TrafficCollector) candidates(ctx context.Context) ([]*t1Uplink, error) {
+	routers, err := t.client.GetLogicalRouters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("t1 traffic: list logical routers: %w", err)
+	}
+	ports, err := t.client.GetLogicalRouterPorts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("t1 traffic: list logical router ports: %w", err)
+	}
+	uplink := map[string]string{} // T1 router ID → link port ID
+	for _, p := range ports {
+		if p.ResourceType == "LogicalRouterLinkPortOnTIER1" {
+			uplink[p.LogicalRouterID] = p.ID
+		}
+	}
+
+	ecNames := map[string]string{}
+	if ecs, err := t.client.GetPolicyEdgeClusters(ctx); err == nil {
+		for _, ec := range ecs {
+			if ec.NSXID != "" {
+				ecNames[ec.NSXID] = ec.DisplayName
+			}
+		}
+	} else {
+		t.logger.Debug("t1 traffic: policy edge-clusters failed", zap.Error(err))
+	}
+
+	// T1 unique_id → (parent T0 name, VRF name).
+	type parentInfo struct{ t0, vrf string }
+	parents := map[string]parentInfo{}
+	t0s, err0 := t.client.GetPolicyTier0s(ctx)
+	t1s, err1 := t.client.GetPolicyTier1s(ctx)
+	if err0 == nil && err1 == nil {
+		t0ByPath := make(map[string]*nsx.PolicyTier0, len(t0s))
+		for i := range t0s {
+			t0ByPath[t0s[i].Path] = &t0s[i]
+		}
+		for i := range t1s {
+			t0 := t0ByPath[t1s[i].Tier0Path]
+			if t0 == nil {
+				continue
+			}
+			pi := parentInfo{t0: t0.DisplayName}
+			if t0.IsVRF() {
+				pi.vrf = t0.DisplayName
+				pi.t0 = "-"
+				if base := t0ByPath[t0.VRFParentPath()]; base != nil {
+					pi.t0 = base.DisplayName
+				}
+			}
+			parents[t1s[i].UniqueID] = pi
+		}
+	} else {
+		t.logger.Debug("t1 traffic: policy tier-0s/tier-1s failed, parents unavailable",
+			zap.NamedError("tier0", err0), zap.NamedError("tier1", err1))
+	}
+
+	var out []*t1Uplink
+	for _, r := range routers {
+		if r.RouterType != "TIER1" {
+			continue
+		}
+		portID, ok := uplink[r.ID]
+		if !ok {
+			continue // standalone T1, no uplink to measure
+		}
+		pi := parents[r.ID]
+		out = append(out, &t1Uplink{
+			id:          r.ID,
+			name:        r.DisplayName,
+			portID:      portID,
+			parentT0:    pi.t0,
+			vrf:         pi.vrf,
+			edgeCluster: ecNames[r.EdgeClusterID],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out, nil
+}
+
+// pick selects this run's T1s according to the sampling mode.
+func (t *T1TrafficCollector) pick(cands []*t1Uplink) []*t1Uplink {
+	if t.cfg.Mode == "all" || len(cands) == 0 {
+		return cands
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var picked []*t1Uplink
+	taken := map[string]bool{}
+	if t.cfg.Mode == "top_n" && t.cfg.TopN > 0 {
+		ranked := make([]*t1Uplink, 0, len(cands))
+		for _, u := range cands {
+			if t.lastBps[u.id] > 0 {
+				ranked = append(ranked, u)
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool { return t.lastBps[ranked[i].id] > t.lastBps[ranked[j].id] })
+		if len(ranked) > t.cfg.TopN {
+			ranked = ranked[:t.cfg.TopN]
+		}
+		for _, u := range ranked {
+			picked = append(picked, u)
+			taken[u.id] = true
+		}
+	}
+
+	// Rotating slice over the rest.
+	n := t.cfg.SampleSize
+	if n <= 0 || n > len(cands) {
+		n = len(cands)
+	}
+	if t.cursor >= len(cands) {
+		t.cursor = 0
+	}
+	for i := 0; i < n; i++ {
+		u := cands[(t.cursor+i)%len(cands)]
+		if !taken[u.id] {
+			picked = append(picked, u)
+			taken[u.id] = true
+		}
+	}
+	t.cursor = (t.cursor + n) % len(cands)
+	return picked
+}
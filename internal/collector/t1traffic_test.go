@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+)
+
+func TestT1TrafficPick(t *testing.T) {
+	cands := []*t1Uplink{{id: "a"}, {id: "b"}, {id: "c"}, {id: "d"}}
+	ids := func(us []*t1Uplink) string {
+		var s []string
+		for _, u := range us {
+			s = append(s, u.id)
+		}
+		return strings.Join(s, ",")
+	}
+	cases := []struct {
+		name    string
+		cfg     config.T1TrafficConfig
+		lastBps map[string]float64
+		want    []string // one entry per consecutive pick
+	}{
+		{
+			name: "all",
+			cfg:  config.T1TrafficConfig{Mode: "all", SampleSize: 1},
+			want: []string{"a,b,c,d", "a,b,c,d"},
+		},
+		{
+			name: "rotating wraps around",
+			cfg:  config.T1TrafficConfig{Mode: "rotating", SampleSize: 3},
+			want: []string{"a,b,c", "d,a,b", "c,d,a"},
+		},
+		{
+			name:    "top_n keeps the busiest and rotates the rest",
+			cfg:     config.T1TrafficConfig{Mode: "top_n", TopN: 2, SampleSize: 1},
+			lastBps: map[string]float64{"c": 300, "a": 100, "d": 200},
+			want:    []string{"c,d,a", "c,d,b", "c,d", "c,d"},
+		},
+		{
+			name: "top_n without history is plain rotation",
+			cfg:  config.T1TrafficConfig{Mode: "top_n", TopN: 2, SampleSize: 2},
+			want: []string{"a,b", "c,d"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewT1TrafficCollector(testSite, nil, NewRateCalculator(), tc.cfg, zap.NewNop())
+			for id, bps := range tc.lastBps {
+				tr.lastBps[id] = bps
+			}
+			for i, want := range tc.want {
+				if got := ids(tr.pick(cands)); got != want {
+					t.Fatalf("pick %d = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestT1TrafficCollect(t *testing.T) {
+	var mu sync.Mutex
+	routers := `{"results":[
+		{"id":"t0","router_type":"TIER0"},
+		{"id":"t1a","display_name":"T1-A","router_type":"TIER1","edge_cluster_id":"ec1"},
+		{"id":"t1b","display_name":"T1-B","router_type":"TIER1","edge_cluster_id":"ec1"},
+		{"id":"t1c","display_name":"T1-C","router_type":"TIER1","edge_cluster_id":"ec1"}]}`
+	var bytes int64
+	c := newFakeNSX(t, map[string]string{
+		"/api/v1/logical-router-ports": `{"results":[
+			{"id":"pa","logical_router_id":"t1a","resource_type":"LogicalRouterLinkPortOnTIER1"},
+			{"id":"pb","logical_router_id":"t1b","resource_type":"LogicalRouterLinkPortOnTIER1"},
+			{"id":"pc","logical_router_id":"t1c","resource_type":"LogicalRouterLinkPortOnTIER1"},
+			{"id":"px","logical_router_id":"t0","resource_type":"LogicalRouterLinkPortOnTIER0"}]}`,
+		"/policy/api/v1/infra/sites/default/enforcement-points/default/edge-clusters": `{"results":[{"nsx_id":"ec1","display_name":"EC-PROD"}]}`,
+		"/policy/api/v1/infra/tier-0s": `{"results":[
+			{"id":"t0","display_name":"T0-CORE","path":"/infra/tier-0s/t0"},
+			{"id":"vrf-a","display_name":"VRF-A","path":"/infra/tier-0s/vrf-a","vrf_config":{"tier0_path":"/infra/tier-0s/t0"}}]}`,
+		"/policy/api/v1/infra/tier-1s": `{"results":[
+			{"id":"a","unique_id":"t1a","path":"/infra/tier-1s/a","tier0_path":"/infra/tier-0s/t0"},
+			{"id":"b","unique_id":"t1b","path":"/infra/tier-1s/b","tier0_path":"/infra/tier-0s/vrf-a"}]}`,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/v1/logical-routers":
+			w.Write([]byte(routers))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/logical-router-ports/"):
+			bytes += 1_000_000
+			b := strconv.FormatInt(bytes, 10)
+			w.Write([]byte(`{"per_node_statistics":[{"rx":{"total_bytes":` + b + `,"total_packets":` + b + `},"tx":{"total_bytes":` + b + `,"total_packets":` + b + `}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	rc := NewRateCalculator()
+	tr := NewT1TrafficCollector(testSite, c, rc, config.T1TrafficConfig{Mode: "all"}, zap.NewNop())
+
+	// Seed a reading a minute ago so the first Collect already has rates.
+	past := time.Now().Add(-time.Minute)
+	for _, id := range []string{"t1a", "t1b", "t1c"} {
+		for _, k := range []string{"rx_bytes", "tx_bytes", "rx_packets", "tx_packets"} {
+			rc.CounterRate(tr.rateKey(id)+k, 0, maxBytesPerSec, past)
+		}
+	}
+	points, err := tr.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	type tags struct{ t0, vrf, ec string }
+	got := map[string]tags{}
+	for _, p := range points {
+		got[pointTag(p, "t1_name")] = tags{pointTag(p, "parent_t0"), pointTag(p, "vrf"), pointTag(p, "edge_cluster")}
+	}
+	want := map[string]tags{
+		"T1-A": {"T0-CORE", "-", "EC-PROD"},
+		"T1-B": {"T0-CORE", "VRF-A", "EC-PROD"},
+		"T1-C": {"-", "-", "EC-PROD"}, // not in the Policy listing
+	}
+	if len(got) != len(want) {
+		t.Fatalf("points = %v, want %v", got, want)
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s tags = %+v, want %+v", name, got[name], w)
+		}
+	}
+
+	// T1-C is deleted: its counters leave the rate calculator.
+	mu.Lock()
+	routers = `{"results":[
+		{"id":"t1a","display_name":"T1-A","router_type":"TIER1"},
+		{"id":"t1b","display_name":"T1-B","router_type":"TIER1"}]}`
+	mu.Unlock()
+	if _, err := tr.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var keys int
+	for key := range rc.state {
+		if strings.HasPrefix(key, tr.rateKey("t1c")) {
+			t.Errorf("counter of a deleted T1 kept: %s", key)
+		}
+		keys++
+	}
+	if keys != 8 {
+		t.Errorf("rate keys = %d, want 8 (4 per live T1)", keys)
+	}
+}
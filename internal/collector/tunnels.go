@@ -0,0 +1,186 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/config"
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// tunnelState is the last observed state of one tunnel.
+type tunnelState struct {
+	status string
+	bfd    string
+}
+
+// TunnelCollector reads every tunnel of the transport nodes (edges only when
+// tunnels.edges_only) and writes, per run, one nsx_tunnel_summary point per
+// node plus nsx_tunnel_event points only for tunnels whose status or BFD
+// state changed — so cardinality follows incidents, not the tunnel mesh.
+// A tunnel seen for the first time is silent when UP and an event when not,
+// so a tunnel already down at startup is still recorded. Previous states
+// live in memory only.
+type TunnelCollector struct {
+	site   string
+	client *nsx.Client
+	logger *zap.Logger
+	cfg    config.TunnelsConfig
+
+	mu sync.Mutex
+	// prev[node_id][tunnel_name] = last state seen.
+	prev map[string]map[string]tunnelState
+}
+
+// NewTunnelCollector creates the tunnel collector for one manager.
+func NewTunnelCollector(site string, client *nsx.Client, cfg config.TunnelsConfig, logger *zap.Logger) *TunnelCollector {
+	return &TunnelCollector{
+		site:   site,
+		client: client,
+		logger: logger,
+		cfg:    cfg,
+		prev:   map[string]map[string]tunnelState{},
+	}
+}
+
+// Interval is how often the worker runs Collect.
+func (t *TunnelCollector) Interval() time.Duration { return t.cfg.Interval }
+
+// Collect reads the tunnels of every selected transport node and returns
+// the summary and change points.
+func (t *TunnelCollector) Collect(ctx context.Context) ([]*write.Point, error) {
+	site := t.site
+	nodes, err := t.client.GetTransportNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tunnels: list transport nodes: %w", err)
+	}
+	edgesOnly := t.cfg.EdgesOnly == nil || *t.cfg.EdgesOnly
+	var selected []nsx.TransportNodeItem
+	for _, n := range nodes {
+		if edgesOnly && !isEdgeNodeType(n.NodeDeploymentInfo.ResourceType) {
+			continue
+		}
+		selected = append(selected, n)
+	}
+
+	now := time.Now()
+	type result struct {
+		tunnels []nsx.Tunnel
+		ok      bool
+	}
+	results := make([]result, len(selected))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < t.client.MaxInFlight(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tunnels, err := t.client.GetTransportNodeTunnels(ctx, selected[i].ID)
+				if err != nil {
+					if !nsx.IsNotFound(err) {
+						t.logger.Debug("tunnels failed", zap.String("node", selected[i].DisplayName), zap.Error(err))
+						telemetry.CollectErrors.WithLabelValues(site, "tunnels").Inc()
+					}
+					continue
+				}
+				results[i] = result{tunnels: tunnels, ok: true}
+			}
+		}()
+	}
+feed:
+	for i := range selected {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var points []*write.Point
+	var down, events int
+	for i, r := range results {
+		if !r.ok {
+			// Unreadable this run: keep the previous states so a failed
+			// read is never reported as tunnels going down.
+			continue
+		}
+		n := &selected[i]
+		nodeType := n.NodeDeploymentInfo.ResourceType
+		if nodeType == "" {
+			nodeType = "HostNode"
+		}
+		prev := t.prev[n.ID]
+		cur := make(map[string]tunnelState, len(r.tunnels))
+		var up, bfdDown int64
+		remotes := map[string]struct{}{}
+		for j := range r.tunnels {
+			tn := &r.tunnels[j]
+			if tn.Up() {
+				up++
+			} else {
+				down++
+			}
+			if !strings.EqualFold(tn.BFD.State, "UP") {
+				bfdDown++
+			}
+			if tn.RemoteNodeID != "" {
+				remotes[tn.RemoteNodeID] = struct{}{}
+			}
+
+			st := tunnelState{status: strings.ToUpper(tn.Status), bfd: strings.ToUpper(tn.BFD.State)}
+			cur[tn.Name] = st
+			old, seen := prev[tn.Name]
+			if seen && old == st {
+				continue
+			}
+			if !seen && tn.Up() {
+				continue
+			}
+			points = append(points, influxpkg.TunnelEventPoint(site, n.ID, n.DisplayName, nodeType, old.status, tn, now))
+			event := "down"
+			if tn.Up() {
+				event = "up"
+			}
+			telemetry.TunnelEvents.WithLabelValues(site, event).Inc()
+			events++
+		}
+		t.prev[n.ID] = cur
+		points = append(points, influxpkg.TunnelSummaryPoint(
+			site, n.ID, n.DisplayName, nodeType,
+			int64(len(r.tunnels)), up, bfdDown, int64(len(remotes)), now,
+		))
+	}
+
+	// Forget transport nodes that no longer exist (or are now filtered out).
+	live := make(map[string]struct{}, len(selected))
+	for i := range selected {
+		live[selected[i].ID] = struct{}{}
+	}
+	for id := range t.prev {
+		if _, ok := live[id]; !ok {
+			delete(t.prev, id)
+		}
+	}
+
+	telemetry.TunnelsDown.WithLabelValues(site).Set(float64(down))
+	t.logger.Debug("tunnels collected",
+		zap.Int("nodes", len(selected)),
+		zap.Int("down", down),
+		zap.Int("events", events),
+	)
+	return points, nil
+}
@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	influxpkg "nsx-collector/internal/influxdb"
+	"nsx-collector/internal/nsx"
+	"nsx-collector/internal/telemetry"
+)
+
+// versionNodeTypes is the node_type order of the version points.
+var versionNodeTypes = []string{"manager", "edge", "host"}
+
+// nodeVersion is the last NSX version reported by one node.
+type nodeVersion struct {
+	nodeType string // manager | edge | host
+	name     string
+	version  string
+}
+
+// versionTracker keeps the NSX version every Manager node, edge and host
+// reported in its status, and compares their release with the one the
+// Manager (node/version) runs; build numbers differ between node types.
+// The worker feeds it from the status reads it already does each cycle, so
+// the inventory costs no extra request. Only used from the worker
+// goroutine.
+type versionTracker struct {
+	site   string
+	logger *zap.Logger
+	// nodes is keyed by node ID (Manager node UUID or transport node ID).
+	nodes map[string]nodeVersion
+}
+
+func newVersionTracker(site string, logger *zap.Logger) *versionTracker {
+	return &versionTracker{site: site, logger: logger, nodes: map[string]nodeVersion{}}
+}
+
+// observe records the version a node reported; empty versions (node down,
+// host without NSX installed yet) are ignored.
+func (v *versionTracker) observe(nodeType, id, name, version string) {
+	if id == "" || version == "" {
+		return
+	}
+	v.nodes[id] = nodeVersion{nodeType: nodeType, name: name, version: version}
+}
+
+// retain forgets nodes of the given types whose ID is not in live, after a
+// complete listing of them (deleted transport nodes, removed Managers).
+func (v *versionTracker) retain(live map[string]bool, nodeTypes ...string) {
+	for id, n := range v.nodes {
+		if live[id] {
+			continue
+		}
+		for _, t := range nodeTypes {
+			if n.nodeType == t {
+				delete(v.nodes, id)
+				break
+			}
+		}
+	}
+}
+
+// retainManagers forgets Managers that left the cluster. Offline members
+// keep their last reported version.
+func (v *versionTracker) retainManagers(cs *nsx.ClusterStatus) {
+	mgmt := &cs.MgmtClusterStatus
+	members := make(map[string]bool, len(mgmt.OnlineNodes)+len(mgmt.OfflineNodes))
+	for _, n := range mgmt.OnlineNodes {
+		members[n.UUID] = true
+	}
+	for _, n := range mgmt.OfflineNodes {
+		members[n.UUID] = true
+	}
+	v.retain(members, "manager")
+}
+
+// points returns the per-version distribution and the site drift flag
+// against managerVersion (the Manager product version), plus one
+// nsx_version point per node when perNode. Nothing is returned until a
+// node has been observed.
+func (v *versionTracker) points(managerVersion string, perNode bool, now time.Time) []*write.Point {
+	if len(v.nodes) == 0 {
+		return nil
+	}
+	site := v.site
+	type distKey struct{ nodeType, version string }
+	dist := map[distKey]int64{}
+	drift := map[string]int64{}
+	versions := map[string]bool{}
+	var points []*write.Point
+
+	ids := make([]string, 0, len(v.nodes))
+	for id := range v.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		n := v.nodes[id]
+		off := managerVersion != "" && !nsx.SameRelease(n.version, managerVersion)
+		if off {
+			drift[n.nodeType]++
+		}
+		dist[distKey{n.nodeType, n.version}]++
+		versions[n.version] = true
+		if perNode {
+			points = append(points, influxpkg.NodeVersionPoint(site, n.nodeType, id, n.name, n.version, off, now))
+		}
+	}
+	for k, count := range dist {
+		onRelease := managerVersion != "" && nsx.SameRelease(k.version, managerVersion)
+		points = append(points, influxpkg.VersionDistributionPoint(site, k.nodeType, k.version, count, onRelease, now))
+	}
+	if managerVersion != "" {
+		points = append(points, influxpkg.VersionDriftPoint(
+			site, managerVersion, drift["manager"], drift["edge"], drift["host"],
+			int64(len(v.nodes)), int64(len(versions)), now,
+		))
+		for _, t := range versionNodeTypes {
+			telemetry.NSXVersionDrift.WithLabelValues(site, t).Set(float64(drift[t]))
+		}
+		if perNode && drift["edge"]+drift["host"] > 0 {
+			v.logger.Info("nsx version drift",
+				zap.String("manager_version", managerVersion),
+				zap.Int64("managers", drift["manager"]),
+				zap.Int64("edges", drift["edge"]),
+				zap.Int64("hosts", drift["host"]),
+			)
+		}
+	}
+	return points
+}
+
+// versionNodeType maps a transport node resource type to node_type.
+func versionNodeType(resourceType string) string {
+	if isEdgeNodeType(resourceType) {
+		return "edge"
+	}
+	return "host"
+}
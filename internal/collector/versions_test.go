@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/nsx"
+)
+
+func TestVersionTrackerDrift(t *testing.T) {
+	v := newVersionTracker(testSite, zap.NewNop())
+	v.observe("manager", "m1", "10.0.0.1", "4.1.2.1.0.22667789")
+	v.observe("edge", "e1", "edge-01", "4.1.2.1.0.22667789")
+	v.observe("edge", "e2", "edge-02", "4.1.1.0.0.22224317")
+	// Host software_version is the NSX VIB version: same release, its own
+	// build, ESXi release after the dash. Not drift.
+	v.observe("host", "h1", "esx-01", "4.1.2.1.0-8.0.22667792")
+	v.observe("host", "h2", "esx-02", "")
+
+	points := v.points("4.1.2.1.0.22667789", true, time.Now())
+	var perNode, dist int
+	var drift *write.Point
+	for _, p := range points {
+		switch p.Name() {
+		case "nsx_version":
+			perNode++
+		case "nsx_version_distribution":
+			dist++
+		case "nsx_version_drift":
+			drift = p
+		}
+	}
+	if perNode != 4 || dist != 4 || drift == nil {
+		t.Fatalf("points: per node %d, distribution %d, drift %v", perNode, dist, drift != nil)
+	}
+	if pointField(drift, "edges_drift") != 1 || pointField(drift, "hosts_drift") != 0 || pointField(drift, "managers_drift") != 0 {
+		t.Errorf("drift fields = %v", drift.FieldList())
+	}
+
+	// The drifting edge is removed: no drift, no per-node points off the slow path.
+	v.retain(map[string]bool{"e1": true, "h1": true}, "edge", "host")
+	for _, p := range v.points("4.1.2.1.0.22667789", false, time.Now()) {
+		if p.Name() == "nsx_version" {
+			t.Fatalf("per-node point on fast path")
+		}
+		if p.Name() == "nsx_version_drift" && pointField(p, "edges_drift") != 0 {
+			t.Errorf("edges_drift after retain = %d", pointField(p, "edges_drift"))
+		}
+	}
+}
+
+func TestVersionTrackerRetainManagers(t *testing.T) {
+	v := newVersionTracker(testSite, zap.NewNop())
+	v.observe("manager", "m1", "10.0.0.1", "4.1.2.1.0.22667789")
+	v.observe("manager", "m2", "10.0.0.2", "4.1.1.0.0.22224317")
+	v.observe("manager", "m3", "10.0.0.3", "4.1.1.0.0.22224317")
+	v.observe("edge", "e1", "edge-01", "4.1.2.1.0.22667789")
+
+	// m2 is down: still a member, its stale version keeps counting as drift.
+	// m3 left the cluster.
+	cs := &nsx.ClusterStatus{}
+	cs.MgmtClusterStatus.OnlineNodes = []nsx.ClusterNode{{UUID: "m1"}}
+	cs.MgmtClusterStatus.OfflineNodes = []nsx.ClusterNode{{UUID: "m2"}}
+	v.retainManagers(cs)
+	for _, id := range []string{"m1", "m2", "e1"} {
+		if _, ok := v.nodes[id]; !ok {
+			t.Errorf("%s forgotten", id)
+		}
+	}
+	if _, ok := v.nodes["m3"]; ok {
+		t.Errorf("m3 kept after leaving the cluster")
+	}
+	for _, p := range v.points("4.1.2.1.0.22667789", false, time.Now()) {
+		if p.Name() == "nsx_version_drift" && pointField(p, "managers_drift") != 1 {
+			t.Errorf("managers_drift = %d, want 1", pointField(p, "managers_drift"))
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"nsx-collector/internal/alerting"
@@ -26,7 +27,22 @@ type Worker struct {
 	haInterval      time.Duration
 	lastHA          time.Time
 	haCollector     *HACollector
+	clusterGroups   *clusterGroupTracker
+	versions        *versionTracker
+	bgpInterval     time.Duration
+	lastBGP         time.Time
+	bgpCollector    *BGPCollector
+	t1Traffic       *T1TrafficCollector
+	lastT1Traffic   time.Time
+	tunnels         *TunnelCollector
+	lastTunnels     time.Time
+	realization     *RealizationCollector
+	lastRealization time.Time
+	fwRuleStats     *FWRuleStatsCollector
+	lastFWRuleStats time.Time
 	capacityCol     *CapacityCollector
+	certCollector   *CertificateCollector
+	backupCol       *BackupCollector
 	// speedOverrides maps node_name -> interface_id -> speed_mbps.
 	// Used to override link_speed when the NSX API returns 0 (fp-* DPDK interfaces).
 	speedOverrides  map[string]map[string]int64
@@ -44,8 +60,11 @@ func NewWorker(
 	rateCalc *RateCalculator,
 	alertEval *alerting.Evaluator,
 	capacityCol *CapacityCollector,
-) *Worker {
-	client := nsx.NewClient(mgr.URL, mgr.Username, mgr.Password, mgr.TLSSkipVerify)
+) (*Worker, error) {
+	client, err := NewClient(mgr)
+	if err != nil {
+		return nil, err
+	}
 	logger := zap.L().Named(mgr.Site)
 	return &Worker{
 		manager:        mgr,
@@ -55,13 +74,77 @@ func NewWorker(
 		slowInterval:   intervals.Slow,
 		haInterval:     intervals.HA,
 		haCollector:    NewHACollector(mgr, client, logger.Named("ha")),
+		clusterGroups:  newClusterGroupTracker(mgr.Site, logger.Named("cluster")),
+		versions:       newVersionTracker(mgr.Site, logger.Named("versions")),
+		bgpInterval:    intervals.BGP,
+		bgpCollector:   NewBGPCollector(mgr.Site, client, logger.Named("bgp")),
 		capacityCol:    capacityCol,
 		speedOverrides: speedOverrides,
 		rateCalc:       rateCalc,
 		alertEval:      alertEval,
+	}, nil
+}
+
+// NewClient builds the NSX API client for one manager from its managers.yaml
+// entry. Shared by the workers and the --print-clusters one-shot so both
+// honour the same connection settings.
+func NewClient(mgr config.Manager) (*nsx.Client, error) {
+	return nsx.NewClient(nsx.Options{
+		Site:     mgr.Site,
+		BaseURL:  mgr.URL,
+		Username: mgr.Username,
+		Password: mgr.Password,
+		TLS: nsx.TLSOptions{
+			SkipVerify:     mgr.TLSSkipVerify,
+			CAFile:         mgr.CAFile,
+			Fingerprints:   mgr.CertFingerprints,
+			ClientCertFile: mgr.ClientCertFile,
+			ClientKeyFile:  mgr.ClientKeyFile,
+		},
+		AuthMode: mgr.AuthMode,
+		RateLimit: nsx.LimiterConfig{
+			RequestsPerSec:    mgr.RateLimit.RequestsPerSec,
+			MinRequestsPerSec: mgr.RateLimit.MinRequestsPerSec,
+			MaxInFlight:       mgr.RateLimit.MaxInFlight,
+			LatencyThreshold:  mgr.RateLimit.LatencyThreshold,
+		},
+		Retry:          retryPolicy(mgr.Retry.RetryPolicyConfig),
+		RetryOverrides: retryOverrides(mgr.Retry.Overrides),
+		Failover: nsx.FailoverConfig{
+			Enabled:          mgr.Failover.Enabled,
+			NodeURLs:         mgr.Failover.NodeURLs,
+			Discover:         mgr.Failover.Discover == nil || *mgr.Failover.Discover,
+			FailbackInterval: mgr.Failover.FailbackInterval,
+		},
+		Cache: nsx.CacheConfig{
+			Enabled: mgr.InventoryCache.Enabled == nil || *mgr.InventoryCache.Enabled,
+			TTL:     mgr.InventoryCache.TTL,
+			TTLs:    mgr.InventoryCache.TTLs,
+		},
+	})
+}
+
+func retryPolicy(p config.RetryPolicyConfig) nsx.RetryPolicy {
+	return nsx.RetryPolicy{
+		MaxAttempts:   p.MaxAttempts,
+		BaseBackoff:   p.BaseBackoff,
+		MaxBackoff:    p.MaxBackoff,
+		Deadline:      p.Deadline,
+		RetryStatuses: p.RetryStatuses,
 	}
 }
 
+func retryOverrides(o map[string]config.RetryPolicyConfig) map[string]nsx.RetryPolicy {
+	if len(o) == 0 {
+		return nil
+	}
+	out := make(map[string]nsx.RetryPolicy, len(o))
+	for class, p := range o {
+		out[class] = retryPolicy(p)
+	}
+	return out
+}
+
 // Client returns the worker's NSX client. Useful for callers that need to
 // inject capacity collectors after construction (when client comes from worker).
 func (w *Worker) Client() *nsx.Client { return w.client }
@@ -70,6 +153,61 @@ func (w *Worker) Client() *nsx.Client { return w.client }
 // worker is built (since the capacity collector needs the worker's client).
 func (w *Worker) SetCapacityCollector(c *CapacityCollector) { w.capacityCol = c }
 
+// SetT1TrafficCollector attaches the optional per-T1 traffic collector
+// (t1_traffic.enabled); it runs on its own cadence (t1_traffic.interval).
+func (w *Worker) SetT1TrafficCollector(c *T1TrafficCollector) { w.t1Traffic = c }
+
+// SetTunnelCollector attaches the optional per-tunnel collector
+// (tunnels.enabled); it runs on its own cadence (tunnels.interval).
+func (w *Worker) SetTunnelCollector(c *TunnelCollector) { w.tunnels = c }
+
+// SetRealizationCollector attaches the optional realization-state collector
+// (realization.enabled); it runs on its own cadence (realization.interval).
+func (w *Worker) SetRealizationCollector(c *RealizationCollector) { w.realization = c }
+
+// SetFWRuleStatsCollector attaches the optional gateway firewall rule
+// statistics collector (fw_rule_stats.enabled); it runs on its own cadence
+// (fw_rule_stats.interval) and writes to the capacity bucket.
+func (w *Worker) SetFWRuleStatsCollector(c *FWRuleStatsCollector) { w.fwRuleStats = c }
+
+// SetCertificateCollector attaches the certificate inventory
+// (certificates.enabled); it runs on the slow path.
+func (w *Worker) SetCertificateCollector(c *CertificateCollector) { w.certCollector = c }
+
+// SetBackupCollector attaches the backup status collector (backup.enabled);
+// it runs on the slow path.
+func (w *Worker) SetBackupCollector(c *BackupCollector) { w.backupCol = c }
+
+// Close releases the worker's NSX session (logout in session auth mode).
+// Called once on shutdown, after the scheduler has stopped.
+func (w *Worker) Close(ctx context.Context) {
+	if err := w.client.Close(ctx); err != nil {
+		w.logger.Warn("nsx logout failed", zap.Error(err))
+	}
+}
+
+// traceNode returns a context that records the endpoint serving the
+// requests made with it, and a func tagging points built from that fetch
+// with it (nsx_node). Points without a request of their own (cache hits,
+// rollups of earlier reads) get the endpoint active when they are tagged.
+// Both are pass-throughs when failover is off.
+func (w *Worker) traceNode(ctx context.Context) (context.Context, func(pts ...*write.Point) []*write.Point) {
+	if !w.client.FailoverEnabled() {
+		return ctx, func(pts ...*write.Point) []*write.Point { return pts }
+	}
+	ctx, trace := nsx.WithNodeTrace(ctx)
+	return ctx, func(pts ...*write.Point) []*write.Point {
+		node := trace.Node()
+		if node == "" {
+			node = w.client.ActiveNode()
+		}
+		for _, p := range pts {
+			p.AddTag("nsx_node", node)
+		}
+		return pts
+	}
+}
+
 // Collect runs a full collection cycle for this manager.
 func (w *Worker) Collect(ctx context.Context) {
 	start := time.Now()
@@ -97,29 +235,97 @@ func (w *Worker) Collect(ctx context.Context) {
 	// HA-state gating: runs on its own cadence (default 1m), independent of
 	// the slow path. First cycle baselines (no change events possible).
 	if w.haInterval > 0 && (w.lastHA.IsZero() || time.Since(w.lastHA) >= w.haInterval) {
-		if haPoints, err := w.haCollector.CollectHA(ctx); err != nil {
+		tctx, tag := w.traceNode(ctx)
+		if haPoints, err := w.haCollector.CollectHA(tctx); err != nil {
 			logger.Warn("ha collection failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "ha").Inc()
 		} else if len(haPoints) > 0 {
-			points = append(points, haPoints...)
+			points = append(points, tag(haPoints...)...)
 		}
 		w.lastHA = now
 	}
 
+	// BGP neighbor state of every T0/VRF, on its own cadence (default 1m;
+	// a negative intervals.bgp turns it off).
+	// First cycle baselines (no down/up events possible).
+	if w.bgpInterval > 0 && (w.lastBGP.IsZero() || time.Since(w.lastBGP) >= w.bgpInterval) {
+		tctx, tag := w.traceNode(ctx)
+		if bgpPoints, err := w.bgpCollector.CollectBGP(tctx); err != nil {
+			logger.Warn("bgp collection failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "bgp").Inc()
+		} else {
+			points = append(points, tag(bgpPoints...)...)
+		}
+		w.lastBGP = now
+	}
+
+	// Per-T1 traffic (optional), sampled per t1_traffic.mode.
+	if w.t1Traffic != nil && (w.lastT1Traffic.IsZero() || time.Since(w.lastT1Traffic) >= w.t1Traffic.Interval()) {
+		tctx, tag := w.traceNode(ctx)
+		if t1Points, err := w.t1Traffic.Collect(tctx); err != nil {
+			logger.Warn("t1 traffic collection failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "t1_traffic").Inc()
+		} else {
+			points = append(points, tag(t1Points...)...)
+		}
+		w.lastT1Traffic = now
+	}
+
+	// Tunnel/BFD inventory (optional): changes + per-node summary.
+	if w.tunnels != nil && (w.lastTunnels.IsZero() || time.Since(w.lastTunnels) >= w.tunnels.Interval()) {
+		tctx, tag := w.traceNode(ctx)
+		if tunnelPoints, err := w.tunnels.Collect(tctx); err != nil {
+			logger.Warn("tunnel collection failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "tunnels").Inc()
+		} else {
+			points = append(points, tag(tunnelPoints...)...)
+		}
+		w.lastTunnels = now
+	}
+
+	// Realization state (optional): failures per type/VRF + change events.
+	if w.realization != nil && (w.lastRealization.IsZero() || time.Since(w.lastRealization) >= w.realization.Interval()) {
+		tctx, tag := w.traceNode(ctx)
+		if realizationPoints, err := w.realization.Collect(tctx); err != nil {
+			logger.Warn("realization collection failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "realization").Inc()
+		} else {
+			points = append(points, tag(realizationPoints...)...)
+		}
+		w.lastRealization = now
+	}
+
+	// Gateway firewall rule hits (optional): per-gateway unused rules.
+	if w.fwRuleStats != nil && (w.lastFWRuleStats.IsZero() || time.Since(w.lastFWRuleStats) >= w.fwRuleStats.Interval()) {
+		tctx, tag := w.traceNode(ctx)
+		if fwPoints, err := w.fwRuleStats.Collect(tctx, now); err != nil {
+			logger.Warn("fw rule stats collection failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "fw_rule_stats").Inc()
+		} else {
+			capacityPoints = append(capacityPoints, tag(fwPoints...)...)
+		}
+		w.lastFWRuleStats = now
+	}
+
 	// 1. Cluster status
-	cs, err := w.client.GetClusterStatus(ctx)
+	tctx, tag := w.traceNode(ctx)
+	cs, err := w.client.GetClusterStatus(tctx)
 	if err != nil {
 		logger.Warn("cluster status failed", zap.Error(err))
 		telemetry.CollectErrors.WithLabelValues(site, "cluster").Inc()
 	} else {
-		points = append(points, influxpkg.ClusterStatusPoint(site, cs, now))
+		w.client.LearnNodes(cs)
+		points = append(points, tag(influxpkg.ClusterStatusPoint(site, cs, now))...)
+		points = append(points, tag(w.clusterGroups.points(cs, now)...)...)
 	}
 
 	// 1b. Uptime de cada Manager do cluster — itera sobre os nós online
 	// retornados em /cluster/status e busca /cluster/nodes/<id>/status.
 	if cs != nil {
+		w.versions.retainManagers(cs)
 		for _, n := range cs.MgmtClusterStatus.OnlineNodes {
-			ns, err := w.client.GetClusterNodeStatus(ctx, n.UUID)
+			tctx, tag := w.traceNode(ctx)
+			ns, err := w.client.GetClusterNodeStatus(tctx, n.UUID)
 			if err != nil {
 				logger.Warn("manager node status failed",
 					zap.String("node", n.UUID),
@@ -128,7 +334,9 @@ func (w *Worker) Collect(ctx context.Context) {
 				telemetry.CollectErrors.WithLabelValues(site, "manager_status").Inc()
 				continue
 			}
-			points = append(points, influxpkg.ManagerStatusPoint(site, n.UUID, n.MgmtClusterListenIPAddress, ns, now))
+			points = append(points, tag(influxpkg.ManagerStatusPoint(site, n.UUID, n.MgmtClusterListenIPAddress, ns, now))...)
+			points = append(points, tag(influxpkg.ManagerResourcePoints(site, n.UUID, n.MgmtClusterListenIPAddress, ns, now)...)...)
+			w.versions.observe("manager", n.UUID, n.MgmtClusterListenIPAddress, ns.Version)
 		}
 	}
 
@@ -138,6 +346,11 @@ func (w *Worker) Collect(ctx context.Context) {
 		logger.Warn("transport nodes list failed", zap.Error(err))
 		telemetry.CollectErrors.WithLabelValues(site, "transport_nodes").Inc()
 	} else {
+		listed := make(map[string]bool, len(nodes))
+		for _, node := range nodes {
+			listed[node.ID] = true
+		}
+		w.versions.retain(listed, "edge", "host")
 		for _, node := range nodes {
 			nodeID := node.ID
 			nodeName := node.DisplayName
@@ -146,7 +359,8 @@ func (w *Worker) Collect(ctx context.Context) {
 				nodeType = "HostNode"
 			}
 
-			ts, err := w.client.GetTransportNodeStatus(ctx, nodeID)
+			tctx, tag := w.traceNode(ctx)
+			ts, err := w.client.GetTransportNodeStatus(tctx, nodeID)
 			if err != nil {
 				logger.Warn("transport node status failed",
 					zap.String("node", nodeName),
@@ -157,7 +371,8 @@ func (w *Worker) Collect(ctx context.Context) {
 			}
 
 			pts := influxpkg.TransportNodeStatusPoints(site, nodeID, nodeName, nodeType, ts, now)
-			points = append(points, pts...)
+			points = append(points, tag(pts...)...)
+			w.versions.observe(versionNodeType(nodeType), nodeID, nodeName, ts.NodeStatus.SoftwareVersion)
 
 			// Collect physical uplink stats for Edge nodes
 			if isEdgeNodeType(nodeType) {
@@ -175,7 +390,8 @@ func (w *Worker) Collect(ctx context.Context) {
 							continue
 						}
 						uplinkCandidates++
-						ifStats, err := w.client.GetTransportNodeInterfaceStats(ctx, nodeID, iface.InterfaceID)
+						tctx, tag := w.traceNode(ctx)
+						ifStats, err := w.client.GetTransportNodeInterfaceStats(tctx, nodeID, iface.InterfaceID)
 						if err != nil {
 							logger.Warn("interface stats failed",
 								zap.String("node", nodeName),
@@ -196,20 +412,27 @@ func (w *Worker) Collect(ctx context.Context) {
 								}
 							}
 						}
-						points = append(points, influxpkg.EdgeUplinkStatsPoint(site, nodeID, nodeName, &ifaceResolved, ifStats, now))
+						points = append(points, tag(influxpkg.EdgeUplinkStatsPoint(site, nodeID, nodeName, &ifaceResolved, ifStats, now))...)
 
-						if rate := w.rateCalc.Calculate(nodeName, iface.InterfaceID, uint64(ifStats.RxBytes), uint64(ifStats.TxBytes), ifaceResolved.LinkSpeed, now); rate != nil {
-							points = append(points, influxpkg.EdgeUplinkRatePoint(
+						if rate := w.rateCalc.Calculate(nodeName, iface.InterfaceID, ifStats, ifaceResolved.LinkSpeed, now); rate != nil {
+							points = append(points, tag(influxpkg.EdgeUplinkRatePoint(
 								site, nodeID, nodeName, iface.InterfaceID,
 								rate.RxBps, rate.TxBps,
 								rate.RxUtilizationPct, rate.TxUtilizationPct,
-								rate.LinkSpeedMbps, now,
-							))
+								rate.LinkSpeedMbps, rate.PacketRates, now,
+							))...)
 							if w.alertEval != nil {
 								w.alertEval.Evaluate(site, nodeName, iface.InterfaceID,
 									rate.RxUtilizationPct, rate.TxUtilizationPct,
 									rate.LinkSpeedMbps, rate.RxBps, rate.TxBps,
-									ifStats.RxErrors, ifStats.TxErrors)
+									alerting.ErrorRates{
+										RxErrorsPerSec: rate.RxErrorsPerSec,
+										TxErrorsPerSec: rate.TxErrorsPerSec,
+										RxDropsPerSec:  rate.RxDropsPerSec,
+										TxDropsPerSec:  rate.TxDropsPerSec,
+										RxDropPct:      rate.RxDropPct,
+										TxDropPct:      rate.TxDropPct,
+									})
 							}
 						}
 					}
@@ -224,14 +447,21 @@ func (w *Worker) Collect(ctx context.Context) {
 		logger.Debug("transport nodes collected", zap.Int("count", len(nodes)))
 	}
 
+	// 2b. NSX versions of Managers, edges and hosts (from the status reads
+	// above): distribution + drift against the Manager release every cycle,
+	// for upgrade progress; the per-node inventory on the slow path only.
+	_, tag = w.traceNode(ctx)
+	points = append(points, tag(w.versions.points(w.client.ProductVersion(), runSlow, now)...)...)
+
 	// 3. Logical routers (T0, T1, VRF) — inventory
-	routers, err := w.client.GetLogicalRouters(ctx)
+	tctx, tag = w.traceNode(ctx)
+	routers, err := w.client.GetLogicalRouters(tctx)
 	if err != nil {
 		logger.Warn("logical routers failed", zap.Error(err))
 		telemetry.CollectErrors.WithLabelValues(site, "logical_routers").Inc()
 	} else {
 		// Build T1→T0 name map using logical router ports
-		t1ToT0Name := buildT1ToT0Map(ctx, w.client, routers, logger)
+		t1ToT0Name := buildT1ToT0Map(tctx, w.client, routers, logger)
 
 		for i := range routers {
 			lr := &routers[i]
@@ -239,32 +469,96 @@ func (w *Worker) Collect(ctx context.Context) {
 			if lr.RouterType == "TIER1" && parentT0 == "" {
 				parentT0 = "N/A"
 			}
-			points = append(points, influxpkg.LogicalRouterPoint(site, parentT0, lr, now))
+			points = append(points, tag(influxpkg.LogicalRouterPoint(site, parentT0, lr, now))...)
 		}
 		logger.Debug("logical routers collected", zap.Int("count", len(routers)))
 	}
 
 	if runSlow {
+		// 3b. Manager version — refreshes the client's capability registry
+		// so the collectors below skip endpoints this release lacks.
+		tctx, tag := w.traceNode(ctx)
+		if nv, err := w.client.RefreshVersion(tctx); err != nil {
+			logger.Warn("manager version failed", zap.Error(err))
+			telemetry.CollectErrors.WithLabelValues(site, "version").Inc()
+		} else {
+			v := w.client.Version()
+			points = append(points, tag(influxpkg.ManagerVersionPoint(site, nv.ProductVersion, v.Major, v.Minor, v.Patch, now))...)
+			telemetry.NSXVersionInfo.DeletePartialMatch(prometheus.Labels{"site": site})
+			telemetry.NSXVersionInfo.WithLabelValues(site, nv.ProductVersion).Set(1)
+			for cp, reason := range w.client.Unsupported() {
+				logger.Debug("nsx capability unavailable", zap.String("capability", string(cp)), zap.String("reason", reason))
+			}
+		}
+
+		// 3c. Manager appliance services (proton, corfu, search, http) on
+		// each online Manager node. A 404 means the release names the
+		// service differently — skipped rather than reported as stopped.
+		if cs != nil {
+			for _, n := range cs.MgmtClusterStatus.OnlineNodes {
+				for _, svc := range nsx.ManagerServices {
+					tctx, tag := w.traceNode(ctx)
+					st, err := w.client.GetManagerServiceStatus(tctx, n.UUID, svc.Name)
+					if err != nil {
+						if !nsx.IsNotFound(err) {
+							logger.Warn("manager service status failed",
+								zap.String("node", n.UUID),
+								zap.String("service", svc.Label),
+								zap.Error(err),
+							)
+							telemetry.CollectErrors.WithLabelValues(site, "manager_services").Inc()
+						}
+						continue
+					}
+					points = append(points, tag(influxpkg.ManagerServicePoint(site, n.UUID, n.MgmtClusterListenIPAddress, svc.Label, st, now))...)
+				}
+			}
+		}
+
 		// 4. Active alarms (NSX faults)
-		alarms, err := w.client.GetActiveAlarms(ctx)
+		tctx, tag = w.traceNode(ctx)
+		alarms, err := w.client.GetActiveAlarms(tctx)
 		if err != nil {
 			logger.Warn("alarms failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "alarms").Inc()
 		} else {
 			for i := range alarms {
-				points = append(points, influxpkg.AlarmPoint(site, &alarms[i], now))
+				points = append(points, tag(influxpkg.AlarmPoint(site, &alarms[i], now))...)
 			}
 			logger.Debug("alarms collected", zap.Int("count", len(alarms)))
 		}
 
+		// 4b. Certificate inventory + expiry notices
+		if w.certCollector != nil {
+			tctx, tag := w.traceNode(ctx)
+			if certPoints, err := w.certCollector.Collect(tctx, now); err != nil {
+				logger.Warn("certificates failed", zap.Error(err))
+				telemetry.CollectErrors.WithLabelValues(site, "certificates").Inc()
+			} else {
+				points = append(points, tag(certPoints...)...)
+			}
+		}
+
+		// 4c. Manager backups: last success/failure + stale notice
+		if w.backupCol != nil {
+			tctx, tag := w.traceNode(ctx)
+			if backupPoints, err := w.backupCol.Collect(tctx, now); err != nil {
+				logger.Warn("backup status failed", zap.Error(err))
+				telemetry.CollectErrors.WithLabelValues(site, "backup").Inc()
+			} else {
+				points = append(points, tag(backupPoints...)...)
+			}
+		}
+
 		// 5. Capacity usage — written to capacity bucket
-		capacities, err := w.client.GetCapacityUsage(ctx)
+		tctx, tag = w.traceNode(ctx)
+		capacities, err := w.client.GetCapacityUsage(tctx)
 		if err != nil {
 			logger.Warn("capacity usage failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "capacity").Inc()
 		} else {
 			for i := range capacities {
-				capacityPoints = append(capacityPoints, influxpkg.CapacityPoint(site, &capacities[i], now))
+				capacityPoints = append(capacityPoints, tag(influxpkg.CapacityPoint(site, &capacities[i], now))...)
 			}
 			logger.Debug("capacity collected", zap.Int("count", len(capacities)))
 		}
@@ -273,21 +567,23 @@ func (w *Worker) Collect(ctx context.Context) {
 		// gateway FW per gateway, groups inventory, NAT-per-T1 (when on),
 		// and the t1watch new-T1 detector + Slack notifier.
 		if w.capacityCol != nil {
-			cp, p := w.capacityCol.Collect(ctx, now)
-			capacityPoints = append(capacityPoints, cp...)
-			points = append(points, p...)
+			tctx, tag := w.traceNode(ctx)
+			cp, p := w.capacityCol.Collect(tctx, now)
+			capacityPoints = append(capacityPoints, tag(cp...)...)
+			points = append(points, tag(p...)...)
 		}
 
 		// 6. NS Services count — written to capacity bucket
-		if svcCount, err := w.client.GetNSServicesCount(ctx); err != nil {
+		tctx, tag = w.traceNode(ctx)
+		if svcCount, err := w.client.GetNSServicesCount(tctx); err != nil {
 			logger.Warn("ns-services count failed", zap.Error(err))
 			telemetry.CollectErrors.WithLabelValues(site, "ns_services").Inc()
 		} else {
-			capacityPoints = append(capacityPoints, influxpkg.CapacityPoint(site, &nsx.CapacityUsageItem{
+			capacityPoints = append(capacityPoints, tag(influxpkg.CapacityPoint(site, &nsx.CapacityUsageItem{
 				UsageType:         "NUMBER_OF_NS_SERVICES",
 				DisplayName:       "NS Services",
 				CurrentUsageCount: svcCount,
-			}, now))
+			}, now))...)
 			logger.Debug("ns-services collected", zap.Int64("count", svcCount))
 		}
 
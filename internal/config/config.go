@@ -18,6 +18,12 @@ type Config struct {
 	Slack           SlackConfig                 `yaml:"slack"`
 	T1Watch         T1WatchConfig               `yaml:"t1_watch"`
 	Capacity        CapacityConfig              `yaml:"capacity"`
+	T1Traffic       T1TrafficConfig             `yaml:"t1_traffic"`
+	Certificates    CertificatesConfig          `yaml:"certificates"`
+	Backup          BackupConfig                `yaml:"backup"`
+	Tunnels         TunnelsConfig               `yaml:"tunnels"`
+	FWRuleStats     FWRuleStatsConfig           `yaml:"fw_rule_stats"`
+	Realization     RealizationConfig           `yaml:"realization"`
 	// InterfaceSpeeds overrides link_speed_mbps for interfaces where the NSX API
 	// returns 0 (common for DPDK/fastpath fp-* interfaces on bare-metal Edge nodes).
 	// Format: node_name -> interface_id -> speed in Mbps.
@@ -45,16 +51,79 @@ type T1WatchConfig struct {
 type CapacityConfig struct {
 	// CollectSegments runs the segments inventory at slow cadence (for B3/D1).
 	CollectSegments bool `yaml:"collect_segments"`
-	// CollectNATPerT1 fetches /tier-1s/{id}/nat/USER/nat-rules?page_size=1 per T1.
-	// On TESP3 (2272 T1s) this means ~2272 extra requests per slow cycle (5min);
-	// paced by NATPerT1PaceMS to avoid rate-limits.
+	// CollectNATPerT1 counts USER NAT rules per T1. With the Policy Search
+	// API (UseSearch) that is a few paginated calls; on Managers without it
+	// the collector falls back to /tier-1s/{id}/nat/USER/nat-rules?page_size=1
+	// per T1 — ~2272 requests per slow cycle on TESP3, paced by the shared
+	// rate_limit (managers.yaml).
 	CollectNATPerT1   bool `yaml:"collect_nat_per_t1"`
-	NATPerT1PaceMS   int `yaml:"nat_per_t1_pace_ms"`
-	NATPerT1Parallel int `yaml:"nat_per_t1_parallel"`
+	// CollectStaticRoutes counts static routes per T0/VRF/T1, same
+	// search-then-fan-out strategy as CollectNATPerT1.
+	CollectStaticRoutes bool `yaml:"collect_static_routes"`
+	// CollectRouteTables reads the routing (RIB) and forwarding (FIB) table
+	// of every T0/VRF per edge node and counts routes by type. Two requests
+	// per gateway per slow cycle, each returning the full table.
+	CollectRouteTables bool `yaml:"collect_route_tables"`
+	// RouteLimitDefault / RouteLimits are the per-table route limits per
+	// T0/VRF display name, same shape as t1_watch.vrf_t1_limits. 0 = no
+	// limit (usage_pct stays 0).
+	RouteLimitDefault int64            `yaml:"route_limit_default"`
+	RouteLimits       map[string]int64 `yaml:"route_limits"`
+	// CollectSRPerEdge reads /logical-routers/<id>/status of every T0/VRF/T1
+	// with an edge cluster and counts service routers (SRs) per edge node
+	// and per edge cluster. One request per router with an SR per slow
+	// cycle, paced by the shared rate_limit.
+	CollectSRPerEdge bool `yaml:"collect_sr_per_edge"`
+	// SRLimitPerEdgeDefault / SREdgeLimits are the SR limit per edge node
+	// (overrides keyed by edge display name); 0 = no limit. Set it from the
+	// VMware configuration maximums for the edge form factor in use.
+	SRLimitPerEdgeDefault int64            `yaml:"sr_limit_per_edge_default"`
+	SREdgeLimits          map[string]int64 `yaml:"sr_edge_limits"`
+	// SRClusterLimits caps the SRs of an edge cluster (keyed by its display
+	// name). Without an override the cluster limit is the sum of the
+	// per-edge limits of its edges seen with SRs.
+	SRClusterLimits map[string]int64 `yaml:"sr_cluster_limits"`
+	// CollectIPPools reads the Policy IP pools (pool_usage), the subnets of
+	// each pool and the IP blocks they are carved from. One request per pool
+	// per slow cycle, plus the two lists.
+	CollectIPPools bool `yaml:"collect_ip_pools"`
+	// IPPoolThresholdPctDefault is the usage_pct threshold of pools and
+	// blocks without an override; over_threshold is set at or above it.
+	// Default 80. IPPoolThresholds (by pool display name) and
+	// IPBlockThresholds (by block display name) override it, same shape as
+	// t1_watch.vrf_t1_limits; a pool and a block may share a name.
+	IPPoolThresholdPctDefault int64            `yaml:"ip_pool_threshold_pct_default"`
+	IPPoolThresholds          map[string]int64 `yaml:"ip_pool_thresholds"`
+	IPBlockThresholds         map[string]int64 `yaml:"ip_block_thresholds"`
+	// UseSearch counts per-gateway objects via /policy/api/v1/search/query
+	// (NAT rules, static routes, segments) and /search/aggregate (gateway
+	// firewall rules) instead of one request per gateway or full lists.
+	// Falls back automatically when the Manager answers 404/501. Defaults
+	// to true (nil = on).
+	UseSearch *bool `yaml:"use_search"`
+	// Incremental keeps the last segment / gateway-policy / NAT counts in
+	// state_dir (capacity-<site>.json) and only re-queries what changed since
+	// the previous cycle (T1 _revision, plus _last_modified_time via search).
+	// Defaults to true (nil = on).
+	Incremental *bool `yaml:"incremental"`
+	// FullResyncInterval forces a full count this often, catching deletions
+	// that leave no change mark (a NAT rule or segment removed). Default 1h.
+	FullResyncInterval time.Duration `yaml:"full_resync_interval"`
 	// CollectGWPolicies runs gateway-policies?include_rule_count=true (B4).
 	CollectGWPolicies bool `yaml:"collect_gw_policies"`
 	// CollectGroups runs the groups inventory at slow cadence (D2).
 	CollectGroups bool `yaml:"collect_groups"`
+	// GroupMembersSample is how many groups get their effective members
+	// read per slow cycle (4 requests each: VMs, IPs, segments, ports),
+	// rotating through every group; results persist in
+	// state_dir/group-members-<site>.json. 0 = off. Needs collect_groups.
+	GroupMembersSample int `yaml:"group_members_sample"`
+	// GroupMembersOversized flags groups with more effective members than
+	// this (all kinds summed). Default 1000.
+	GroupMembersOversized int64 `yaml:"group_members_oversized"`
+	// GroupRulesTopN is how many groups referenced by the most DFW rules
+	// are reported (Policy search). Default 20.
+	GroupRulesTopN int `yaml:"group_rules_top_n"`
 	// TrackT1Events runs the t1watch detector and writes nsx_t1_event points
 	// (created/deleted) to InfluxDB every slow cycle, INDEPENDENT of the Slack
 	// notifier. The external t1-slack-bot consumes those events, so this must
@@ -63,6 +132,90 @@ type CapacityConfig struct {
 	TrackT1Events *bool `yaml:"track_t1_events"`
 }
 
+// T1TrafficConfig controls per-T1 traffic statistics (nsx_t1_bandwidth),
+// read from the T1 side of each T1→T0 link port. One request per T1 polled,
+// so large sites poll a bounded sample per run instead of every T1.
+type T1TrafficConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // default 1m
+	// Mode selects which T1s are polled each run:
+	//   all      — every T1 (small sites only)
+	//   rotating — SampleSize T1s per run, cycling through all of them
+	//   top_n    — the TopN busiest (by last rate) every run, plus a
+	//              rotating slice of SampleSize to discover new heavy hitters
+	// Default top_n.
+	Mode       string `yaml:"mode"`
+	TopN       int    `yaml:"top_n"`       // default 50
+	SampleSize int    `yaml:"sample_size"` // default 100
+}
+
+// TunnelsConfig controls the per-tunnel inventory (nsx_tunnel_event,
+// nsx_tunnel_summary) read from /api/v1/transport-nodes/<id>/tunnels. One
+// request per transport node per run, so it is off by default and can be
+// limited to edges.
+type TunnelsConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // default 5m
+	// EdgesOnly skips host transport nodes. Defaults to true (nil = on):
+	// hosts outnumber edges by far and each one costs a request per run.
+	EdgesOnly *bool `yaml:"edges_only"`
+}
+
+// FWRuleStatsConfig controls gateway firewall rule statistics and unused
+// rule detection (nsx_fw_rule_usage, capacity bucket). One request per
+// gateway policy per run.
+type FWRuleStatsConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // default 1h
+	// UnusedDays is how long a rule's hit count must stay flat to count as
+	// unused. A rule must also have been observed for that long. Default 30.
+	UnusedDays int `yaml:"unused_days"`
+	// ExportDir receives fw-stale-rules-<site>.{csv,json} every run.
+	// Default t1_watch.state_dir.
+	ExportDir string `yaml:"export_dir"`
+	// ExportFormats is any of csv, json. Default both; empty list = none.
+	ExportFormats []string `yaml:"export_formats"`
+}
+
+// RealizationConfig controls realization-state tracking: the configuration
+// state of every transport node (one paginated list) plus the Policy
+// realized status of T0/VRF, T1 and segments (one request per object read).
+type RealizationConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // default 5m
+	// SampleSize is how many Policy objects get their realized status read
+	// per run, rotating through all of them. Objects last seen in ERROR or
+	// IN_PROGRESS are re-read every run on top of it. Default 200.
+	SampleSize int `yaml:"sample_size"`
+}
+
+// CertificatesConfig controls the certificate inventory (nsx_certificate),
+// read every slow cycle from /api/v1/trust-management/certificates.
+type CertificatesConfig struct {
+	// Enabled defaults to true (nil = on).
+	Enabled *bool `yaml:"enabled"`
+	// NotifyDays are the days-to-expiry thresholds that post a Slack notice,
+	// once per certificate and threshold (plus once when it expires).
+	// Default [60, 30, 7]. Crossed thresholds are kept in
+	// t1_watch.state_dir/certificates-<site>.json across restarts.
+	NotifyDays []int `yaml:"notify_days"`
+	// SlackChannel overrides slack.channel for certificate notices.
+	SlackChannel string `yaml:"slack_channel"`
+}
+
+// BackupConfig controls the Manager backup status (nsx_backup), read every
+// slow cycle from /api/v1/cluster/backups/{overview,history}.
+type BackupConfig struct {
+	// Enabled defaults to true (nil = on).
+	Enabled *bool `yaml:"enabled"`
+	// MaxAge is how old the last successful cluster backup may get before a
+	// Slack notice is posted. Default 25h (daily schedule plus slack). The
+	// notified state is kept in t1_watch.state_dir/backup-<site>.json.
+	MaxAge time.Duration `yaml:"max_age"`
+	// SlackChannel overrides slack.channel for backup notices.
+	SlackChannel string `yaml:"slack_channel"`
+}
+
 // SlackConfig holds Slack alerting settings.
 type SlackConfig struct {
 	Enabled     bool   `yaml:"enabled"`
@@ -103,6 +256,7 @@ type IntervalConfig struct {
 	Traffic time.Duration `yaml:"traffic"` // for interface throughput (future)
 	Slow    time.Duration `yaml:"slow"`    // for alarms, capacity, LB (changes slowly)
 	HA      time.Duration `yaml:"ha"`      // for T0/T1 HA state of observed SRs (default 1m)
+	BGP     time.Duration `yaml:"bgp"`     // for BGP neighbor state of every T0/VRF (default 1m; negative = off)
 }
 
 // LoadConfig reads and parses the collector config file.
@@ -168,23 +322,91 @@ func (c *Config) setDefaults() {
 	if c.Intervals.HA == 0 {
 		c.Intervals.HA = 1 * time.Minute
 	}
+	if c.Intervals.BGP == 0 {
+		c.Intervals.BGP = 1 * time.Minute
+	}
+	if c.T1Traffic.Interval == 0 {
+		c.T1Traffic.Interval = 1 * time.Minute
+	}
+	if c.T1Traffic.Mode == "" {
+		c.T1Traffic.Mode = "top_n"
+	}
+	if c.T1Traffic.TopN == 0 {
+		c.T1Traffic.TopN = 50
+	}
+	if c.T1Traffic.SampleSize == 0 {
+		c.T1Traffic.SampleSize = 100
+	}
+	if c.Tunnels.Interval == 0 {
+		c.Tunnels.Interval = 5 * time.Minute
+	}
+	if c.Tunnels.EdgesOnly == nil {
+		on := true
+		c.Tunnels.EdgesOnly = &on
+	}
+	if c.Realization.Interval == 0 {
+		c.Realization.Interval = 5 * time.Minute
+	}
+	if c.Realization.SampleSize == 0 {
+		c.Realization.SampleSize = 200
+	}
+	if c.FWRuleStats.Interval == 0 {
+		c.FWRuleStats.Interval = time.Hour
+	}
+	if c.FWRuleStats.UnusedDays == 0 {
+		c.FWRuleStats.UnusedDays = 30
+	}
+	if c.FWRuleStats.ExportFormats == nil {
+		c.FWRuleStats.ExportFormats = []string{"csv", "json"}
+	}
+	if c.Certificates.Enabled == nil {
+		on := true
+		c.Certificates.Enabled = &on
+	}
+	if c.Certificates.NotifyDays == nil {
+		c.Certificates.NotifyDays = []int{60, 30, 7}
+	}
+	if c.Backup.Enabled == nil {
+		on := true
+		c.Backup.Enabled = &on
+	}
+	if c.Backup.MaxAge == 0 {
+		c.Backup.MaxAge = 25 * time.Hour
+	}
 	if c.T1Watch.StateDir == "" {
 		c.T1Watch.StateDir = "/home/nsx_collector/state"
 	}
+	if c.FWRuleStats.ExportDir == "" {
+		c.FWRuleStats.ExportDir = c.T1Watch.StateDir
+	}
 	if c.T1Watch.VRFT1LimitDefault == 0 {
 		c.T1Watch.VRFT1LimitDefault = 200
 	}
 	if c.T1Watch.T0T1LimitDefault == 0 {
 		c.T1Watch.T0T1LimitDefault = 1000
 	}
-	if c.Capacity.TrackT1Events == nil {
+	if c.Capacity.UseSearch == nil {
 		on := true
-		c.Capacity.TrackT1Events = &on
+		c.Capacity.UseSearch = &on
+	}
+	if c.Capacity.Incremental == nil {
+		on := true
+		c.Capacity.Incremental = &on
+	}
+	if c.Capacity.FullResyncInterval == 0 {
+		c.Capacity.FullResyncInterval = 1 * time.Hour
 	}
-	if c.Capacity.NATPerT1PaceMS == 0 {
-		c.Capacity.NATPerT1PaceMS = 30 // 30ms × 2272 T1s = ~68s per slow cycle on TESP3
+	if c.Capacity.IPPoolThresholdPctDefault == 0 {
+		c.Capacity.IPPoolThresholdPctDefault = 80
 	}
-	if c.Capacity.NATPerT1Parallel == 0 {
-		c.Capacity.NATPerT1Parallel = 4
+	if c.Capacity.GroupMembersOversized == 0 {
+		c.Capacity.GroupMembersOversized = 1000
+	}
+	if c.Capacity.GroupRulesTopN == 0 {
+		c.Capacity.GroupRulesTopN = 20
+	}
+	if c.Capacity.TrackT1Events == nil {
+		on := true
+		c.Capacity.TrackT1Events = &on
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,6 +23,38 @@ type Manager struct {
 	TLSSkipVerify bool `yaml:"tls_skip_verify"`
 	Enabled     bool   `yaml:"enabled"`
 
+	// CAFile is a PEM bundle trusted for the Manager certificate instead of
+	// the system roots.
+	CAFile string `yaml:"ca_file"`
+	// CertFingerprints pins the Manager certificate by SHA-256 (hex, colons
+	// optional, e.g. the value from `openssl x509 -fingerprint -sha256`).
+	// Without ca_file the pin alone is trusted (self-signed certs).
+	CertFingerprints []string `yaml:"cert_fingerprints"`
+	// ClientCertFile / ClientKeyFile: client certificate presented to the
+	// Manager (principal identity). Both or neither.
+	ClientCertFile string `yaml:"client_cert_file"`
+	ClientKeyFile  string `yaml:"client_key_file"`
+
+	// AuthMode selects how the collector authenticates against the Manager:
+	// "basic" (Basic Auth on every request, default) or "session" (log in
+	// once via /api/session/create and reuse the JSESSIONID cookie).
+	AuthMode string `yaml:"auth_mode"`
+
+	// RateLimit is the request budget shared by every collector talking to
+	// this Manager. See RateLimitConfig.
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// Retry is the retry policy for transient NSX failures. See RetryConfig.
+	Retry RetryConfig `yaml:"retry"`
+
+	// Failover lets the collector talk to individual Manager nodes when the
+	// cluster VIP (URL) is down. See FailoverConfig.
+	Failover FailoverConfig `yaml:"failover"`
+
+	// InventoryCache shares inventory lists (logical routers, transport
+	// nodes, T0/T1s...) between the Worker, HA and Capacity collectors.
+	InventoryCache InventoryCacheConfig `yaml:"inventory_cache"`
+
 	// HAWatch controls how the HA collector chooses which T1s to observe
 	// per T0 edge cluster. See HAWatchConfig.
 	HAWatch HAWatchConfig `yaml:"ha_watch"`
@@ -50,6 +83,76 @@ type HAWatchConfig struct {
 	T1Names []string `yaml:"t1_names"`
 }
 
+// RateLimitConfig tunes the adaptive token bucket in front of the NSX client.
+// The budget halves on HTTP 429 (and shrinks 20% on slow responses), then
+// grows back gradually towards RequestsPerSec while the Manager is healthy.
+// Zero fields are left alone here and take the defaults of nsx.NewLimiter.
+type RateLimitConfig struct {
+	// RequestsPerSec is the steady-state budget. Default: 25.
+	RequestsPerSec float64 `yaml:"requests_per_sec"`
+	// MinRequestsPerSec is the backoff floor. Default: 2.
+	MinRequestsPerSec float64 `yaml:"min_requests_per_sec"`
+	// MaxInFlight caps concurrent requests to this Manager. Default: 8.
+	MaxInFlight int `yaml:"max_in_flight"`
+	// LatencyThreshold marks a response as a latency spike. Default: 3s.
+	LatencyThreshold time.Duration `yaml:"latency_threshold"`
+}
+
+// RetryConfig is the retry policy for transient NSX failures (statuses in
+// RetryStatuses, connection resets, timeouts). Overrides replaces fields of
+// the base policy per endpoint class: "status" (cheap per-object status
+// calls) or "inventory" (paged lists).
+type RetryConfig struct {
+	RetryPolicyConfig `yaml:",inline"`
+	Overrides         map[string]RetryPolicyConfig `yaml:"overrides"`
+}
+
+// RetryPolicyConfig is one retry policy. Zero fields inherit the base policy
+// (for overrides) or the defaults of nsx.RetryPolicy (for the base); config
+// leaves them zero.
+type RetryPolicyConfig struct {
+	// MaxAttempts counts the first try. Default: 4.
+	MaxAttempts int `yaml:"max_attempts"`
+	// BaseBackoff doubles per attempt (with jitter) up to MaxBackoff.
+	// Defaults: 500ms / 10s.
+	BaseBackoff time.Duration `yaml:"base_backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	// Deadline bounds one call, attempts and waits included. Default: 60s.
+	Deadline time.Duration `yaml:"deadline"`
+	// RetryStatuses default: [429, 502, 503, 504].
+	RetryStatuses []int `yaml:"retry_statuses"`
+}
+
+// FailoverConfig lists the Manager nodes the collector may use when the VIP
+// stops answering (connection errors, 502/503/504). Nodes come from NodeURLs
+// and, with Discover, from mgmt_cluster_listen_ip_address in
+// /api/v1/cluster/status. When enabled, points carry an nsx_node tag ("vip"
+// or the node address) telling which endpoint served them.
+type FailoverConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// NodeURLs are explicit node base URLs, tried in order after the VIP.
+	NodeURLs []string `yaml:"node_urls"`
+	// Discover learns node addresses from cluster status. Default: true.
+	Discover *bool `yaml:"discover"`
+	// FailbackInterval: how often the VIP is re-probed while on a node.
+	// Default: 5m.
+	FailbackInterval time.Duration `yaml:"failback_interval"`
+}
+
+// InventoryCacheConfig controls the per-manager inventory cache. Concurrent
+// callers of the same list share one request (single-flight) and later
+// callers reuse the result for TTL.
+type InventoryCacheConfig struct {
+	// Enabled defaults to true.
+	Enabled *bool `yaml:"enabled"`
+	// TTL for resources not listed in TTLs. Default: 30s.
+	TTL time.Duration `yaml:"ttl"`
+	// TTLs per resource: transport_nodes, logical_routers,
+	// logical_router_ports, policy_tier0s, policy_tier1s,
+	// policy_edge_clusters. 0s = always refetch (single-flight only).
+	TTLs map[string]time.Duration `yaml:"ttls"`
+}
+
 // LoadManagers reads and parses the managers inventory file.
 func LoadManagers(path string) ([]Manager, error) {
 	data, err := os.ReadFile(path)
@@ -79,6 +182,19 @@ func LoadManagers(path string) ([]Manager, error) {
 		if m.Password == "" {
 			return nil, fmt.Errorf("manager %s: env var %s not set", m.Site, m.PasswordEnv)
 		}
+		if m.TLSSkipVerify && (m.CAFile != "" || len(m.CertFingerprints) > 0) {
+			return nil, fmt.Errorf("manager %s: tls_skip_verify conflicts with ca_file/cert_fingerprints", m.Site)
+		}
+		if (m.ClientCertFile == "") != (m.ClientKeyFile == "") {
+			return nil, fmt.Errorf("manager %s: client_cert_file and client_key_file must be set together", m.Site)
+		}
+		switch m.AuthMode {
+		case "":
+			m.AuthMode = "basic"
+		case "basic", "session":
+		default:
+			return nil, fmt.Errorf("manager %s: invalid auth_mode %q (want basic or session)", m.Site, m.AuthMode)
+		}
 		// Defaults for HA watch / state_dir
 		if m.HAWatch.Mode == "" {
 			m.HAWatch.Mode = "auto"
@@ -89,6 +205,36 @@ func LoadManagers(path string) ([]Manager, error) {
 		if m.StateDir == "" {
 			m.StateDir = "/home/nsx_collector/state"
 		}
+		if m.Failover.Discover == nil {
+			on := true
+			m.Failover.Discover = &on
+		}
+		if m.Failover.FailbackInterval <= 0 {
+			m.Failover.FailbackInterval = 5 * time.Minute
+		}
+		for i, u := range m.Failover.NodeURLs {
+			m.Failover.NodeURLs[i] = strings.TrimRight(u, "/")
+		}
+		if m.InventoryCache.Enabled == nil {
+			on := true
+			m.InventoryCache.Enabled = &on
+		}
+		if m.InventoryCache.TTL <= 0 {
+			m.InventoryCache.TTL = 30 * time.Second
+		}
+		for class := range m.Retry.Overrides {
+			if class != "status" && class != "inventory" {
+				return nil, fmt.Errorf("manager %s: invalid retry override %q (want status or inventory)", m.Site, class)
+			}
+		}
+		for res := range m.InventoryCache.TTLs {
+			switch res {
+			case "transport_nodes", "logical_routers", "logical_router_ports",
+				"policy_tier0s", "policy_tier1s", "policy_edge_clusters":
+			default:
+				return nil, fmt.Errorf("manager %s: unknown inventory_cache resource %q", m.Site, res)
+			}
+		}
 		enabled = append(enabled, m)
 	}
 
@@ -1,6 +1,7 @@
 package influxdb
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,14 @@ func boolInt(b bool) int64 {
 	return 0
 }
 
+// pct returns used/total as a percentage, 0 when total is unknown.
+func pct(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
+
 // ManagerStatusPoint writes the appliance uptime of one NSX Manager from a
 // cluster. Tagged with site, manager_id (UUID) and manager_ip so each Manager
 // can be plotted as a separate series.
@@ -44,6 +53,208 @@ func ManagerStatusPoint(site, managerID, managerIP string, ns *nsx.NodeStatus, n
 	)
 }
 
+// ManagerResourcePoints writes the appliance resources of one NSX Manager,
+// read from the same /cluster/nodes/<id>/status as ManagerStatusPoint.
+// measurement: nsx_manager_resource
+// tags: site, manager_id, manager_ip, scope=node|filesystem, name ("-" for
+//       node, the mount point for filesystem)
+// fields (node): cpu_cores, cpu_avg_pct, cpu_peak_pct, load_avg_1m/5m/15m,
+//                mem_total_kb, mem_used_kb, mem_cache_kb, mem_used_pct,
+//                swap_total_kb, swap_used_kb
+// fields (filesystem): total_kb, used_kb, used_pct
+func ManagerResourcePoints(site, managerID, managerIP string, ns *nsx.NodeStatus, now time.Time) []*write.Point {
+	if managerIP == "" {
+		managerIP = "-"
+	}
+	tags := func(scope, name string) map[string]string {
+		return map[string]string{
+			"site":       site,
+			"manager_id": managerID,
+			"manager_ip": managerIP,
+			"scope":      scope,
+			"name":       name,
+		}
+	}
+	sys := ns.SystemStatus
+	var load1, load5, load15 float64
+	if len(sys.LoadAverage) >= 3 {
+		load1, load5, load15 = sys.LoadAverage[0], sys.LoadAverage[1], sys.LoadAverage[2]
+	}
+	points := []*write.Point{influxdb2.NewPoint(
+		"nsx_manager_resource",
+		tags("node", "-"),
+		map[string]interface{}{
+			"cpu_cores":     int64(sys.CPUCores),
+			"cpu_avg_pct":   sys.CPUUsage.AvgCPUCoreNonDPDK,
+			"cpu_peak_pct":  sys.CPUUsage.HighestCPUCoreNonDPDK,
+			"load_avg_1m":   load1,
+			"load_avg_5m":   load5,
+			"load_avg_15m":  load15,
+			"mem_total_kb":  sys.MemTotal,
+			"mem_used_kb":   sys.MemUsed,
+			"mem_cache_kb":  sys.MemCache,
+			"mem_used_pct":  pct(sys.MemUsed, sys.MemTotal),
+			"swap_total_kb": sys.SwapTotal,
+			"swap_used_kb":  sys.SwapUsed,
+		},
+		now,
+	)}
+	for _, fs := range sys.FileSystems {
+		if fs.Mount == "" {
+			continue
+		}
+		points = append(points, influxdb2.NewPoint(
+			"nsx_manager_resource",
+			tags("filesystem", fs.Mount),
+			map[string]interface{}{
+				"total_kb": fs.Total,
+				"used_kb":  fs.Used,
+				"used_pct": pct(fs.Used, fs.Total),
+			},
+			now,
+		))
+	}
+	return points
+}
+
+// ManagerServicePoint writes the state of one Manager appliance service.
+// measurement: nsx_manager_resource
+// tags: site, manager_id, manager_ip, scope=service, name (proton, corfu,
+//       search, http), state (runtime_state as reported, "-" when empty)
+// fields: running (0/1)
+func ManagerServicePoint(site, managerID, managerIP, service string, st *nsx.NodeServiceStatus, now time.Time) *write.Point {
+	if managerIP == "" {
+		managerIP = "-"
+	}
+	state := st.RuntimeState
+	if state == "" {
+		state = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_manager_resource",
+		map[string]string{
+			"site":       site,
+			"manager_id": managerID,
+			"manager_ip": managerIP,
+			"scope":      "service",
+			"name":       service,
+			"state":      state,
+		},
+		map[string]interface{}{
+			"running": boolInt(st.Running()),
+		},
+		now,
+	)
+}
+
+// ManagerVersionPoint records the NSX release a site's Manager runs.
+// measurement: nsx_manager_version
+// tags: site, version (product_version, e.g. 3.2.3.1.0.22104592)
+// fields: major, minor, patch
+func ManagerVersionPoint(site, version string, major, minor, patch int, now time.Time) *write.Point {
+	if version == "" {
+		version = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_manager_version",
+		map[string]string{
+			"site":    site,
+			"version": version,
+		},
+		map[string]interface{}{
+			"major": int64(major),
+			"minor": int64(minor),
+			"patch": int64(patch),
+		},
+		now,
+	)
+}
+
+// NodeVersionPoint records the NSX version one Manager, edge or host runs.
+// measurement: nsx_version
+// tags: site, node_type=manager|edge|host, node_id, node_name, version,
+//       build
+// fields: drift (1 = different release than the Manager, see
+//         nsx.SameRelease), major, minor, patch
+func NodeVersionPoint(site, nodeType, nodeID, nodeName, version string, drift bool, now time.Time) *write.Point {
+	v := nsx.ParseVersion(version)
+	build := nsx.BuildNumber(version)
+	if build == "" {
+		build = "-"
+	}
+	if nodeName == "" {
+		nodeName = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_version",
+		map[string]string{
+			"site":      site,
+			"node_type": nodeType,
+			"node_id":   nodeID,
+			"node_name": nodeName,
+			"version":   version,
+			"build":     build,
+		},
+		map[string]interface{}{
+			"drift": boolInt(drift),
+			"major": int64(v.Major),
+			"minor": int64(v.Minor),
+			"patch": int64(v.Patch),
+		},
+		now,
+	)
+}
+
+// VersionDistributionPoint records how many nodes of one type run one
+// version — the upgrade-progress view.
+// measurement: nsx_version_distribution
+// tags: site, node_type=manager|edge|host, version
+// fields: nodes, manager_release (1 = same release as the Manager)
+func VersionDistributionPoint(site, nodeType, version string, nodes int64, managerRelease bool, now time.Time) *write.Point {
+	return influxdb2.NewPoint(
+		"nsx_version_distribution",
+		map[string]string{
+			"site":      site,
+			"node_type": nodeType,
+			"version":   version,
+		},
+		map[string]interface{}{
+			"nodes":           nodes,
+			"manager_release": boolInt(managerRelease),
+		},
+		now,
+	)
+}
+
+// VersionDriftPoint flags a site whose nodes do not all run the Manager's
+// release.
+// measurement: nsx_version_drift
+// tags: site, manager_version
+// fields: drift (1 = any edge or host off the Manager release),
+//         managers_drift, edges_drift, hosts_drift (nodes off the release),
+//         nodes, versions (distinct versions seen)
+func VersionDriftPoint(site, managerVersion string, managersDrift, edgesDrift, hostsDrift, nodes, versions int64, now time.Time) *write.Point {
+	if managerVersion == "" {
+		managerVersion = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_version_drift",
+		map[string]string{
+			"site":            site,
+			"manager_version": managerVersion,
+		},
+		map[string]interface{}{
+			"drift":          boolInt(edgesDrift+hostsDrift > 0),
+			"managers_drift": managersDrift,
+			"edges_drift":    edgesDrift,
+			"hosts_drift":    hostsDrift,
+			"nodes":          nodes,
+			"versions":       versions,
+		},
+		now,
+	)
+}
+
 // ClusterStatusPoint converts NSX cluster status to an InfluxDB point.
 func ClusterStatusPoint(site string, cs *nsx.ClusterStatus, now time.Time) *write.Point {
 	return influxdb2.NewPoint(
@@ -63,6 +274,102 @@ func ClusterStatusPoint(site string, cs *nsx.ClusterStatus, now time.Time) *writ
 	)
 }
 
+// clusterStatusNum maps a cluster group status to a number for panels:
+// 2=STABLE, 1=DEGRADED, 0=UNAVAILABLE or anything else.
+func clusterStatusNum(s string) int64 {
+	switch strings.ToUpper(s) {
+	case "STABLE":
+		return 2
+	case "DEGRADED":
+		return 1
+	}
+	return 0
+}
+
+// ClusterGroupPoint records the health of one cluster service group.
+// measurement: nsx_cluster_group
+// tags: site, group_type, group_id, status
+// fields: status_num (2=STABLE, 1=DEGRADED, 0=UNAVAILABLE), members_up,
+//         members_total
+func ClusterGroupPoint(site string, g *nsx.ClusterGroup, now time.Time) *write.Point {
+	status := g.GroupStatus
+	if status == "" {
+		status = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_cluster_group",
+		map[string]string{
+			"site":       site,
+			"group_type": g.GroupType,
+			"group_id":   g.GroupID,
+			"status":     status,
+		},
+		map[string]interface{}{
+			"status_num":    clusterStatusNum(g.GroupStatus),
+			"members_up":    int64(g.MembersUp()),
+			"members_total": int64(len(g.Members)),
+		},
+		now,
+	)
+}
+
+// ClusterMemberPoint records one node's status within a cluster group.
+// measurement: nsx_cluster_member
+// tags: site, group_type, member_uuid, member_ip, member_fqdn, status
+// fields: up (0/1)
+func ClusterMemberPoint(site, groupType string, m *nsx.ClusterGroupMember, now time.Time) *write.Point {
+	ip, fqdn, status := m.MemberIP, m.MemberFQDN, m.MemberStatus
+	if ip == "" {
+		ip = "-"
+	}
+	if fqdn == "" {
+		fqdn = "-"
+	}
+	if status == "" {
+		status = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_cluster_member",
+		map[string]string{
+			"site":        site,
+			"group_type":  groupType,
+			"member_uuid": m.MemberUUID,
+			"member_ip":   ip,
+			"member_fqdn": fqdn,
+			"status":      status,
+		},
+		map[string]interface{}{
+			"up": boolInt(strings.EqualFold(m.MemberStatus, "UP")),
+		},
+		now,
+	)
+}
+
+// ClusterGroupEventPoint records a cluster group changing status between two
+// consecutive cycles.
+// measurement: nsx_cluster_group_event
+// tags: site, group_type, event=degraded|unavailable|recovered, from_status,
+//       to_status
+// fields: count=1, members_up, members_total
+func ClusterGroupEventPoint(site, event, fromStatus string, g *nsx.ClusterGroup, now time.Time) *write.Point {
+	return influxdb2.NewPoint(
+		"nsx_cluster_group_event",
+		map[string]string{
+			"site":        site,
+			"group_type":  g.GroupType,
+			"event":       event,
+			"from_status": fromStatus,
+			"to_status":   g.GroupStatus,
+		},
+		map[string]interface{}{
+			"count":         int64(1),
+			"members_up":    int64(g.MembersUp()),
+			"members_total": int64(len(g.Members)),
+		},
+		now,
+	)
+}
+
 // TransportNodeStatusPoints converts a transport node status to InfluxDB points.
 // Returns one nsx_transport_node point, and optionally one nsx_edge_resource point for edge nodes.
 func TransportNodeStatusPoints(site, nodeID, nodeName, nodeType string, ts *nsx.TransportNodeStatus, now time.Time) []*write.Point {
@@ -508,6 +815,131 @@ func HAChangeEventPoint(site, t0ClusterID, t0Name, fromActive, toActive, fromAct
 	)
 }
 
+// ---------------------------------------------------------------------------
+// Per-T1 traffic from the T1→T0 link port (T1TrafficCollector)
+// ---------------------------------------------------------------------------
+
+// T1BandwidthPoint records the computed traffic of one T1 on its uplink to
+// the T0/VRF, summed over the edge nodes carrying the port. rx is traffic
+// entering the T1 from the T0 (towards the customer), tx the opposite.
+// measurement: nsx_t1_bandwidth
+// tags: site, t1_id, t1_name, parent_t0, vrf, edge_cluster
+// fields: rx_bps, tx_bps, rx_pps, tx_pps
+func T1BandwidthPoint(site, t1ID, t1Name, parentT0, vrf, edgeCluster string, rxBps, txBps, rxPps, txPps float64, now time.Time) *write.Point {
+	if t1Name == "" {
+		t1Name = "-"
+	}
+	if parentT0 == "" {
+		parentT0 = "-"
+	}
+	if vrf == "" {
+		vrf = "-"
+	}
+	if edgeCluster == "" {
+		edgeCluster = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_t1_bandwidth",
+		map[string]string{
+			"site":         site,
+			"t1_id":        t1ID,
+			"t1_name":      t1Name,
+			"parent_t0":    parentT0,
+			"vrf":          vrf,
+			"edge_cluster": edgeCluster,
+		},
+		map[string]interface{}{
+			"rx_bps": rxBps,
+			"tx_bps": txBps,
+			"rx_pps": rxPps,
+			"tx_pps": txPps,
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// BGP neighbor sessions per T0/VRF (BGPCollector)
+// ---------------------------------------------------------------------------
+
+// BGPNeighborPoint records one BGP session (neighbor × edge node) of a T0 or
+// VRF. flaps is NSX's cumulative connection_drop_count — use difference() in
+// Flux for flaps per window.
+// measurement: nsx_bgp_neighbor
+// tags: site, gateway_kind=t0|vrf, gateway_name, gateway_id, edge_id,
+//       edge_name, neighbor, remote_as, state
+// fields: state_num (1=ESTABLISHED), uptime_s, prefixes_received,
+//         prefixes_advertised, flaps, established_count
+func BGPNeighborPoint(site, gatewayKind, gatewayName, gatewayID, edgeID, edgeName string, n *nsx.BGPNeighborStatus, now time.Time) *write.Point {
+	if edgeID == "" {
+		edgeID = "-"
+	}
+	if edgeName == "" {
+		edgeName = "-"
+	}
+	remoteAS := n.RemoteASNumber
+	if remoteAS == "" {
+		remoteAS = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_bgp_neighbor",
+		map[string]string{
+			"site":         site,
+			"gateway_kind": gatewayKind,
+			"gateway_name": gatewayName,
+			"gateway_id":   gatewayID,
+			"edge_id":      edgeID,
+			"edge_name":    edgeName,
+			"neighbor":     n.NeighborAddress,
+			"remote_as":    remoteAS,
+			"state":        strings.ToUpper(strings.TrimSpace(n.ConnectionState)),
+		},
+		map[string]interface{}{
+			"state_num":           boolInt(n.Established()),
+			"uptime_s":            n.TimeSinceEstablished / 1000,
+			"prefixes_received":   n.TotalInPrefixCount,
+			"prefixes_advertised": n.TotalOutPrefixCount,
+			"flaps":               n.ConnectionDropCount,
+			"established_count":   n.EstablishedConnectionCount,
+		},
+		now,
+	)
+}
+
+// BGPEventPoint records a BGP session going down (ESTABLISHED → anything
+// else) or coming back up between two consecutive BGP polls.
+// measurement: nsx_bgp_event
+// tags: site, event=down|up, gateway_kind, gateway_name, edge_name, neighbor,
+//       remote_as, from_state, to_state
+// fields: count=1, flaps
+func BGPEventPoint(site, event, gatewayKind, gatewayName, edgeName, neighbor, remoteAS, fromState, toState string, flaps int64, now time.Time) *write.Point {
+	if edgeName == "" {
+		edgeName = "-"
+	}
+	if remoteAS == "" {
+		remoteAS = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_bgp_event",
+		map[string]string{
+			"site":         site,
+			"event":        event,
+			"gateway_kind": gatewayKind,
+			"gateway_name": gatewayName,
+			"edge_name":    edgeName,
+			"neighbor":     neighbor,
+			"remote_as":    remoteAS,
+			"from_state":   fromState,
+			"to_state":     toState,
+		},
+		map[string]interface{}{
+			"count": int64(1),
+			"flaps": flaps,
+		},
+		now,
+	)
+}
+
 // EdgeUplinkStatsPoint converts interface stats for a physical Edge uplink to an InfluxDB point.
 // All fields are cumulative counters — use derivative() in Flux to compute throughput rates.
 // link_speed_mbps is the negotiated link speed in Mbps (0 = unknown/not connected).
@@ -535,10 +967,20 @@ func EdgeUplinkStatsPoint(site, nodeID, nodeName string, iface *nsx.NetworkInter
 	)
 }
 
+// PacketRates are the per-second packet, drop and error rates of an uplink,
+// derived by the collector from the cumulative interface counters. Drop pct
+// is drops / (delivered + drops).
+type PacketRates struct {
+	RxPps, TxPps                   float64
+	RxDropsPerSec, TxDropsPerSec   float64
+	RxErrorsPerSec, TxErrorsPerSec float64
+	RxDropPct, TxDropPct           float64
+}
+
 // EdgeUplinkRatePoint writes pre-calculated bandwidth rates for an Edge uplink.
 // Unlike EdgeUplinkStatsPoint (cumulative counters), these are ready-to-display
 // rate values — no derivative() needed in Grafana.
-func EdgeUplinkRatePoint(site, nodeID, nodeName, ifaceID string, rxBps, txBps, rxUtilPct, txUtilPct float64, linkSpeedMbps int64, now time.Time) *write.Point {
+func EdgeUplinkRatePoint(site, nodeID, nodeName, ifaceID string, rxBps, txBps, rxUtilPct, txUtilPct float64, linkSpeedMbps int64, pr PacketRates, now time.Time) *write.Point {
 	return influxdb2.NewPoint(
 		"nsx_edge_bandwidth",
 		map[string]string{
@@ -553,6 +995,14 @@ func EdgeUplinkRatePoint(site, nodeID, nodeName, ifaceID string, rxBps, txBps, r
 			"rx_utilization_pct":  rxUtilPct,
 			"tx_utilization_pct":  txUtilPct,
 			"link_speed_mbps":     linkSpeedMbps,
+			"rx_pps":              pr.RxPps,
+			"tx_pps":              pr.TxPps,
+			"rx_drops_per_sec":    pr.RxDropsPerSec,
+			"tx_drops_per_sec":    pr.TxDropsPerSec,
+			"rx_errors_per_sec":   pr.RxErrorsPerSec,
+			"tx_errors_per_sec":   pr.TxErrorsPerSec,
+			"rx_drop_pct":         pr.RxDropPct,
+			"tx_drop_pct":         pr.TxDropPct,
 		},
 		now,
 	)
@@ -822,6 +1272,90 @@ func NATPerT1Point(site, t1ID, t1Name, parentKind, parentName string, count int6
 	)
 }
 
+// StaticRoutesPerGatewayPoint records the static route count of one gateway.
+// measurement: nsx_static_routes_per_gateway
+// tags: site, gateway_kind=t1|vrf|t0, gateway_name, gateway_id
+// fields: static_routes
+func StaticRoutesPerGatewayPoint(site, gatewayKind, gatewayName, gatewayID string, count int64, now time.Time) *write.Point {
+	if gatewayName == "" {
+		gatewayName = "-"
+	}
+	if gatewayID == "" {
+		gatewayID = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_static_routes_per_gateway",
+		map[string]string{
+			"site":         site,
+			"gateway_kind": gatewayKind,
+			"gateway_name": gatewayName,
+			"gateway_id":   gatewayID,
+		},
+		map[string]interface{}{
+			"static_routes": count,
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Route table size per T0/VRF and edge node
+// ---------------------------------------------------------------------------
+
+// RouteTablePoint records the size of one route table (RIB or FIB) of a
+// T0/VRF service router on one edge node, split by route category, against
+// the configured per-gateway route limit (capacity.route_limits).
+// measurement: nsx_route_table
+// tags: site, gateway_kind=t0|vrf, gateway_name, gateway_id, edge_id,
+//       edge_name, table=rib|fib
+// fields: total, connected, static, bgp, nat, other, limit, usage_pct, available
+func RouteTablePoint(site, gatewayKind, gatewayName, gatewayID, edgeID, edgeName, table string, byCategory map[string]int64, total, limit int64, now time.Time) *write.Point {
+	if gatewayName == "" {
+		gatewayName = "-"
+	}
+	if gatewayID == "" {
+		gatewayID = "-"
+	}
+	if edgeID == "" {
+		edgeID = "-"
+	}
+	if edgeName == "" {
+		edgeName = "-"
+	}
+	avail := limit - total
+	if avail < 0 {
+		avail = 0
+	}
+	var pct float64
+	if limit > 0 {
+		pct = float64(total) / float64(limit) * 100
+	}
+	return influxdb2.NewPoint(
+		"nsx_route_table",
+		map[string]string{
+			"site":         site,
+			"gateway_kind": gatewayKind,
+			"gateway_name": gatewayName,
+			"gateway_id":   gatewayID,
+			"edge_id":      edgeID,
+			"edge_name":    edgeName,
+			"table":        table,
+		},
+		map[string]interface{}{
+			"total":     total,
+			"connected": byCategory["connected"],
+			"static":    byCategory["static"],
+			"bgp":       byCategory["bgp"],
+			"nat":       byCategory["nat"],
+			"other":     byCategory["other"],
+			"limit":     limit,
+			"usage_pct": pct,
+			"available": avail,
+		},
+		now,
+	)
+}
+
 // ---------------------------------------------------------------------------
 // Gateway firewall rules per T1 / per VRF
 // ---------------------------------------------------------------------------
@@ -854,6 +1388,112 @@ func FWPerGatewayPoint(site, gatewayKind, gatewayName, gatewayID string, ruleCou
 	)
 }
 
+// FWRuleUsagePoint records the rule hit statistics and unused rules of one
+// gateway, from gateway-policies/<id>/statistics.
+// measurement: nsx_fw_rule_usage
+// tags: site, gateway_kind=t1|vrf|t0, gateway_name, gateway_id
+// fields: rules (with statistics), unused (no new hit for
+//         fw_rule_stats.unused_days), unused_pct, hit_count, packet_count,
+//         byte_count, session_count (cumulative, summed over the rules)
+func FWRuleUsagePoint(site, gatewayKind, gatewayName, gatewayID string, rules, unused, hits, packets, bytes, sessions int64, now time.Time) *write.Point {
+	return influxdb2.NewPoint(
+		"nsx_fw_rule_usage",
+		map[string]string{
+			"site":         site,
+			"gateway_kind": gatewayKind,
+			"gateway_name": gatewayName,
+			"gateway_id":   gatewayID,
+		},
+		map[string]interface{}{
+			"rules":         rules,
+			"unused":        unused,
+			"unused_pct":    pct(unused, rules),
+			"hit_count":     hits,
+			"packet_count":  packets,
+			"byte_count":    bytes,
+			"session_count": sessions,
+		},
+		now,
+	)
+}
+
+// IPPoolPoint records the usage of one Policy IP pool (pool_usage).
+// measurement: nsx_ip_pool
+// tags: site, pool_id, pool_name
+// fields: total, allocated, free, requested, subnets, block_subnets,
+//         usage_pct (allocated/total), threshold_pct, over_threshold
+func IPPoolPoint(site string, p *nsx.IPPool, subnets, blockSubnets, thresholdPct int64, now time.Time) *write.Point {
+	name := p.DisplayName
+	if name == "" {
+		name = "-"
+	}
+	total := int64(p.PoolUsage.TotalIPs)
+	allocated := int64(p.PoolUsage.AllocatedIPAllocations)
+	usage := pct(allocated, total)
+	return influxdb2.NewPoint(
+		"nsx_ip_pool",
+		map[string]string{
+			"site":      site,
+			"pool_id":   p.ID,
+			"pool_name": name,
+		},
+		map[string]interface{}{
+			"total":          total,
+			"allocated":      allocated,
+			"free":           int64(p.PoolUsage.AvailableIPs),
+			"requested":      int64(p.PoolUsage.RequestedIPAllocations),
+			"subnets":        subnets,
+			"block_subnets":  blockSubnets,
+			"usage_pct":      usage,
+			"threshold_pct":  thresholdPct,
+			"over_threshold": boolInt(thresholdPct > 0 && usage >= float64(thresholdPct)),
+		},
+		now,
+	)
+}
+
+// IPBlockPoint records how much of one Policy IP block is carved into pool
+// subnets.
+// measurement: nsx_ip_block
+// tags: site, block_id, block_name, cidr
+// fields: total (addresses in the CIDR), allocated (sum of pool subnet
+//         sizes), free, subnets, usage_pct, threshold_pct, over_threshold
+func IPBlockPoint(site string, b *nsx.IPBlock, allocated, subnets, thresholdPct int64, now time.Time) *write.Point {
+	name := b.DisplayName
+	if name == "" {
+		name = "-"
+	}
+	cidr := b.CIDR
+	if cidr == "" {
+		cidr = "-"
+	}
+	total := b.Size()
+	free := total - allocated
+	if free < 0 {
+		free = 0
+	}
+	usage := pct(allocated, total)
+	return influxdb2.NewPoint(
+		"nsx_ip_block",
+		map[string]string{
+			"site":       site,
+			"block_id":   b.ID,
+			"block_name": name,
+			"cidr":       cidr,
+		},
+		map[string]interface{}{
+			"total":          total,
+			"allocated":      allocated,
+			"free":           free,
+			"subnets":        subnets,
+			"usage_pct":      usage,
+			"threshold_pct":  thresholdPct,
+			"over_threshold": boolInt(thresholdPct > 0 && usage >= float64(thresholdPct)),
+		},
+		now,
+	)
+}
+
 // ---------------------------------------------------------------------------
 // Groups inventory (for waste/orphan analysis) — fed by Policy API groups
 // ---------------------------------------------------------------------------
@@ -879,6 +1519,70 @@ func GroupsInventoryPoint(site string, total, empty int64, now time.Time) *write
 	)
 }
 
+// GroupMembersPoint records the effective members of one group flagged by
+// the rotating member sampler: criteria matching nothing, oversized, or
+// among the groups referenced by the most DFW rules. Member counts are from
+// the group's last sample; -1 = not sampled yet.
+// measurement: nsx_group_members
+// tags: site, group_id, group_name
+// fields: vms, ips, segments, ports, total, dfw_rules (-1 = not counted
+//         this cycle), zero_members, oversized, sampled_at (epoch s, 0 = never)
+func GroupMembersPoint(site, groupID, groupName string, vms, ips, segments, ports, dfwRules int64, zeroMembers, oversized bool, sampledAt, now time.Time) *write.Point {
+	if groupName == "" {
+		groupName = "-"
+	}
+	total := int64(-1)
+	var sampled int64
+	if !sampledAt.IsZero() {
+		total = vms + ips + segments + ports
+		sampled = sampledAt.Unix()
+	}
+	return influxdb2.NewPoint(
+		"nsx_group_members",
+		map[string]string{
+			"site":       site,
+			"group_id":   groupID,
+			"group_name": groupName,
+		},
+		map[string]interface{}{
+			"vms":          vms,
+			"ips":          ips,
+			"segments":     segments,
+			"ports":        ports,
+			"total":        total,
+			"dfw_rules":    dfwRules,
+			"zero_members": boolInt(zeroMembers),
+			"oversized":    boolInt(oversized),
+			"sampled_at":   sampled,
+		},
+		now,
+	)
+}
+
+// GroupMembersSummaryPoint records the coverage and findings of the group
+// member sampler.
+// measurement: nsx_group_members_summary
+// tags: site
+// fields: groups, sampled (this cycle), known (with a sample on file),
+//         coverage_pct, zero_members, oversized, max_dfw_rules (-1 = not
+//         counted this cycle)
+func GroupMembersSummaryPoint(site string, groups, sampled, known, zeroMembers, oversized, maxDFWRules int64, now time.Time) *write.Point {
+	return influxdb2.NewPoint(
+		"nsx_group_members_summary",
+		map[string]string{"site": site},
+		map[string]interface{}{
+			"groups":        groups,
+			"sampled":       sampled,
+			"known":         known,
+			"coverage_pct":  pct(known, groups),
+			"zero_members":  zeroMembers,
+			"oversized":     oversized,
+			"max_dfw_rules": maxDFWRules,
+		},
+		now,
+	)
+}
+
 // ---------------------------------------------------------------------------
 // T1 lifecycle events (create / delete) — fed by t1watch detector
 // ---------------------------------------------------------------------------
@@ -936,3 +1640,301 @@ func SiteT1TotalsPoint(site string, total, onVRF, onT0 int64, now time.Time) *wr
 		now,
 	)
 }
+
+// ---------------------------------------------------------------------------
+// Certificates — trust-management inventory with days to expiry
+// ---------------------------------------------------------------------------
+
+// CertificatePoint records one certificate and how long until it expires.
+// measurement: nsx_certificate
+// tags: site, cert_id, cert_name, used_by (service types, comma-separated),
+//       issuer, self_signed=true|false, severity (OK until the first
+//       certificates.notify_days threshold, then WARNING within 60 days, HIGH
+//       within 30, CRITICAL within 7 or expired; self-signed one level lower)
+// fields: not_after (epoch s), days_remaining (negative once expired),
+//         expired (0/1), severity_num
+func CertificatePoint(site, certID, certName, usedBy, issuer string, selfSigned bool, severity string, notAfter time.Time, daysRemaining int64, now time.Time) *write.Point {
+	if certName == "" {
+		certName = "-"
+	}
+	if usedBy == "" {
+		usedBy = "-"
+	}
+	if issuer == "" {
+		issuer = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_certificate",
+		map[string]string{
+			"site":        site,
+			"cert_id":     certID,
+			"cert_name":   certName,
+			"used_by":     usedBy,
+			"issuer":      issuer,
+			"self_signed": strconv.FormatBool(selfSigned),
+			"severity":    severity,
+		},
+		map[string]interface{}{
+			"not_after":      notAfter.Unix(),
+			"days_remaining": daysRemaining,
+			"expired":        boolInt(daysRemaining < 0),
+			"severity_num":   severityNum(severity),
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Manager backups — fed by /cluster/backups/{overview,history}
+// ---------------------------------------------------------------------------
+
+// BackupPoint records the backup state of one backup type of a site.
+// measurement: nsx_backup
+// tags: site, backup_type=cluster|node|inventory, last_error (error_code of
+//       the latest attempt when it failed, "-" otherwise)
+// fields: enabled (0/1, scheduled backups on), last_ok (latest attempt
+//         succeeded), last_success / last_failure (epoch s, 0 = none in
+//         history), age_hours (since last success, -1 = none), attempts,
+//         failures (in the history window)
+//
+// Backup size is not written: the NSX API does not report it.
+func BackupPoint(site, backupType, lastError string, enabled, lastOK bool, lastSuccess, lastFailure time.Time, ageHours float64, attempts, failures int64, now time.Time) *write.Point {
+	if lastError == "" {
+		lastError = "-"
+	}
+	unix := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+	return influxdb2.NewPoint(
+		"nsx_backup",
+		map[string]string{
+			"site":        site,
+			"backup_type": backupType,
+			"last_error":  lastError,
+		},
+		map[string]interface{}{
+			"enabled":      boolInt(enabled),
+			"last_ok":      boolInt(lastOK),
+			"last_success": unix(lastSuccess),
+			"last_failure": unix(lastFailure),
+			"age_hours":    ageHours,
+			"attempts":     attempts,
+			"failures":     failures,
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Tunnels — per transport node, changes only + summary
+// ---------------------------------------------------------------------------
+
+// TunnelSummaryPoint records the tunnel totals of one transport node.
+// measurement: nsx_tunnel_summary
+// tags: site, node_id, node_name, node_type
+// fields: total, up, down (any status other than UP), bfd_down (BFD state
+//         not UP), remote_nodes (distinct remote transport nodes)
+func TunnelSummaryPoint(site, nodeID, nodeName, nodeType string, total, up, bfdDown, remoteNodes int64, now time.Time) *write.Point {
+	return influxdb2.NewPoint(
+		"nsx_tunnel_summary",
+		map[string]string{
+			"site":      site,
+			"node_id":   nodeID,
+			"node_name": nodeName,
+			"node_type": nodeType,
+		},
+		map[string]interface{}{
+			"total":        total,
+			"up":           up,
+			"down":         total - up,
+			"bfd_down":     bfdDown,
+			"remote_nodes": remoteNodes,
+		},
+		now,
+	)
+}
+
+// TunnelEventPoint records one tunnel changing status or BFD state between
+// two runs, or first seen not UP.
+// measurement: nsx_tunnel_event
+// tags: site, node_id, node_name, node_type, event=down|up, tunnel_name,
+//       remote_node_id, remote_node_name, local_ip, remote_ip, encap,
+//       from_state ("-" when first seen), to_state, bfd_state, bfd_diag
+// fields: count=1, up (0/1)
+func TunnelEventPoint(site, nodeID, nodeName, nodeType, fromState string, t *nsx.Tunnel, now time.Time) *write.Point {
+	event := "down"
+	if t.Up() {
+		event = "up"
+	}
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	return influxdb2.NewPoint(
+		"nsx_tunnel_event",
+		map[string]string{
+			"site":             site,
+			"node_id":          nodeID,
+			"node_name":        nodeName,
+			"node_type":        nodeType,
+			"event":            event,
+			"tunnel_name":      dash(t.Name),
+			"remote_node_id":   dash(t.RemoteNodeID),
+			"remote_node_name": dash(t.RemoteNodeDisplayName),
+			"local_ip":         dash(t.LocalIP),
+			"remote_ip":        dash(t.RemoteIP),
+			"encap":            dash(t.Encap),
+			"from_state":       dash(fromState),
+			"to_state":         dash(t.Status),
+			"bfd_state":        dash(t.BFD.State),
+			"bfd_diag":         dash(t.BFD.Diagnostic),
+		},
+		map[string]interface{}{
+			"count": int64(1),
+			"up":    boolInt(t.Up()),
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Realization state — transport node state + Policy realized status
+// ---------------------------------------------------------------------------
+
+// RealizationSummaryPoint records the realization states of one object type
+// under one parent.
+// measurement: nsx_realization_summary
+// tags: site, object_type=transport_node|t0|vrf|t1|segment, parent (VRF/T0
+//       the object hangs from, "-" for transport nodes and standalone objects)
+// fields: objects (in inventory), known (with a status read), error,
+//         in_progress
+func RealizationSummaryPoint(site, objectType, parent string, objects, known, errors, inProgress int64, now time.Time) *write.Point {
+	if parent == "" {
+		parent = "-"
+	}
+	return influxdb2.NewPoint(
+		"nsx_realization_summary",
+		map[string]string{
+			"site":        site,
+			"object_type": objectType,
+			"parent":      parent,
+		},
+		map[string]interface{}{
+			"objects":     objects,
+			"known":       known,
+			"error":       errors,
+			"in_progress": inProgress,
+		},
+		now,
+	)
+}
+
+// RealizationEventPoint records one object entering or leaving ERROR
+// between two reads, or first seen in ERROR.
+// measurement: nsx_realization_event
+// tags: site, object_type, object_id, object_name, parent,
+//       event=failed|recovered, from_state ("-" when first seen), to_state
+// fields: count=1, message (failure message; transport nodes only)
+func RealizationEventPoint(site, objectType, objectID, objectName, parent, event, fromState, toState, message string, now time.Time) *write.Point {
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	return influxdb2.NewPoint(
+		"nsx_realization_event",
+		map[string]string{
+			"site":        site,
+			"object_type": objectType,
+			"object_id":   dash(objectID),
+			"object_name": dash(objectName),
+			"parent":      dash(parent),
+			"event":       event,
+			"from_state":  dash(fromState),
+			"to_state":    dash(toState),
+		},
+		map[string]interface{}{
+			"count":   int64(1),
+			"message": message,
+		},
+		now,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Service routers per edge node / edge cluster — fed by logical-router status
+// ---------------------------------------------------------------------------
+
+// ServiceRouterEdgePoint records the SRs placed on one edge node.
+// measurement: nsx_sr_per_edge
+// tags: site, edge_id, edge_name, edge_cluster
+// fields: total, t0, vrf, t1, limit (0 = none), usage_pct, available
+func ServiceRouterEdgePoint(site, edgeID, edgeName, edgeCluster string, t0, vrf, t1, limit int64, now time.Time) *write.Point {
+	if edgeName == "" {
+		edgeName = edgeID
+	}
+	if edgeCluster == "" {
+		edgeCluster = "-"
+	}
+	total := t0 + vrf + t1
+	var available int64
+	if limit > 0 {
+		available = limit - total
+	}
+	return influxdb2.NewPoint(
+		"nsx_sr_per_edge",
+		map[string]string{
+			"site":         site,
+			"edge_id":      edgeID,
+			"edge_name":    edgeName,
+			"edge_cluster": edgeCluster,
+		},
+		map[string]interface{}{
+			"total":     total,
+			"t0":        t0,
+			"vrf":       vrf,
+			"t1":        t1,
+			"limit":     limit,
+			"usage_pct": pct(total, limit),
+			"available": available,
+		},
+		now,
+	)
+}
+
+// ServiceRouterClusterPoint records the SRs placed on one edge cluster.
+// measurement: nsx_sr_per_edge_cluster
+// tags: site, edge_cluster
+// fields: total, edges (with at least one SR), max_per_edge, limit (0 =
+//         none), usage_pct, available
+func ServiceRouterClusterPoint(site, edgeCluster string, total, edges, maxPerEdge, limit int64, now time.Time) *write.Point {
+	if edgeCluster == "" {
+		edgeCluster = "-"
+	}
+	var available int64
+	if limit > 0 {
+		available = limit - total
+	}
+	return influxdb2.NewPoint(
+		"nsx_sr_per_edge_cluster",
+		map[string]string{
+			"site":         site,
+			"edge_cluster": edgeCluster,
+		},
+		map[string]interface{}{
+			"total":        total,
+			"edges":        edges,
+			"max_per_edge": maxPerEdge,
+			"limit":        limit,
+			"usage_pct":    pct(total, limit),
+			"available":    available,
+		},
+		now,
+	)
+}
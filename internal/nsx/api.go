@@ -17,6 +17,17 @@ func (c *Client) GetClusterNodeStatus(ctx context.Context, nodeID string) (*Node
 	return &result, nil
 }
 
+// GetManagerServiceStatus returns the state of one appliance service on a
+// specific Manager node, proxied by the cluster API
+// (/api/v1/cluster/<node-id>/node/services/<name>/status).
+func (c *Client) GetManagerServiceStatus(ctx context.Context, nodeID, service string) (*NodeServiceStatus, error) {
+	var result NodeServiceStatus
+	if err := c.doGet(ctx, "/api/v1/cluster/"+nodeID+"/node/services/"+service+"/status", &result); err != nil {
+		return nil, fmt.Errorf("manager %s service %s status: %w", nodeID, service, err)
+	}
+	return &result, nil
+}
+
 // GetClusterStatus returns the overall NSX cluster status.
 func (c *Client) GetClusterStatus(ctx context.Context) (*ClusterStatus, error) {
 	var result ClusterStatus
@@ -26,8 +37,8 @@ func (c *Client) GetClusterStatus(ctx context.Context) (*ClusterStatus, error) {
 	return &result, nil
 }
 
-// GetTransportNodes returns all transport nodes, paginating automatically.
-func (c *Client) GetTransportNodes(ctx context.Context) ([]TransportNodeItem, error) {
+// listTransportNodes returns all transport nodes, paginating automatically.
+func (c *Client) listTransportNodes(ctx context.Context) ([]TransportNodeItem, error) {
 	var all []TransportNodeItem
 	cursor := ""
 	for {
@@ -57,8 +68,8 @@ func (c *Client) GetTransportNodeStatus(ctx context.Context, nodeID string) (*Tr
 	return &result, nil
 }
 
-// GetLogicalRouters returns all logical routers, paginating automatically.
-func (c *Client) GetLogicalRouters(ctx context.Context) ([]LogicalRouter, error) {
+// listLogicalRouters returns all logical routers, paginating automatically.
+func (c *Client) listLogicalRouters(ctx context.Context) ([]LogicalRouter, error) {
 	var all []LogicalRouter
 	cursor := ""
 	for {
@@ -91,9 +102,9 @@ func (c *Client) GetLogicalRouterStatus(ctx context.Context, lrID string) (*Logi
 	return &result, nil
 }
 
-// GetLogicalRouterPorts returns all logical router ports, paginating automatically.
+// listLogicalRouterPorts returns all logical router ports, paginating automatically.
 // Used to build the T1→T0 parent mapping via LinkedRouterPort entries.
-func (c *Client) GetLogicalRouterPorts(ctx context.Context) ([]LogicalRouterPort, error) {
+func (c *Client) listLogicalRouterPorts(ctx context.Context) ([]LogicalRouterPort, error) {
 	var all []LogicalRouterPort
 	cursor := ""
 	for {
@@ -114,6 +125,17 @@ func (c *Client) GetLogicalRouterPorts(ctx context.Context) ([]LogicalRouterPort
 	return all, nil
 }
 
+// GetLogicalRouterPortStats returns the cumulative counters of one logical
+// router port. source=cached reads the Manager's copy (refreshed by NSX about
+// every minute) instead of querying the edges in real time.
+func (c *Client) GetLogicalRouterPortStats(ctx context.Context, portID string) (*LogicalRouterPortStatistics, error) {
+	var result LogicalRouterPortStatistics
+	if err := c.doGet(ctx, "/api/v1/logical-router-ports/"+portID+"/statistics?source=cached", &result); err != nil {
+		return nil, fmt.Errorf("logical router port %s statistics: %w", portID, err)
+	}
+	return &result, nil
+}
+
 // GetTransportNodeInterfaces returns all network interfaces of a transport node.
 func (c *Client) GetTransportNodeInterfaces(ctx context.Context, nodeID string) ([]NetworkInterface, error) {
 	var result NetworkInterfaceList
@@ -277,9 +299,9 @@ func (c *Client) GetLBNodeUsageSummary(ctx context.Context) (*LBNodeUsageSummary
 	return &r, nil
 }
 
-// GetPolicyTier0s lists all Policy API Tier-0 gateways (regular + VRF).
+// listPolicyTier0s lists all Policy API Tier-0 gateways (regular + VRF).
 // VRFs are distinguished by PolicyTier0.IsVRF() (vrf_config presence).
-func (c *Client) GetPolicyTier0s(ctx context.Context) ([]PolicyTier0, error) {
+func (c *Client) listPolicyTier0s(ctx context.Context) ([]PolicyTier0, error) {
 	var all []PolicyTier0
 	cursor := ""
 	for {
@@ -300,9 +322,9 @@ func (c *Client) GetPolicyTier0s(ctx context.Context) ([]PolicyTier0, error) {
 	return all, nil
 }
 
-// GetPolicyTier1s lists all Policy API Tier-1 gateways with their tier0_path
+// listPolicyTier1s lists all Policy API Tier-1 gateways with their tier0_path
 // (which points to either a regular T0 or a VRF — see PolicyTier0.IsVRF).
-func (c *Client) GetPolicyTier1s(ctx context.Context) ([]PolicyTier1, error) {
+func (c *Client) listPolicyTier1s(ctx context.Context) ([]PolicyTier1, error) {
 	var all []PolicyTier1
 	cursor := ""
 	for {
@@ -323,6 +345,50 @@ func (c *Client) GetPolicyTier1s(ctx context.Context) ([]PolicyTier1, error) {
 	return all, nil
 }
 
+// GetPolicyTier1 returns one Policy API Tier-1 by ID. Used to confirm that a
+// T1 missing from the listing is really gone (IsNotFound) before the t1watch
+// snapshot forgets it.
+func (c *Client) GetPolicyTier1(ctx context.Context, tier1ID string) (*PolicyTier1, error) {
+	var result PolicyTier1
+	if err := c.doGet(ctx, "/policy/api/v1/infra/tier-1s/"+tier1ID, &result); err != nil {
+		return nil, fmt.Errorf("policy tier-1 %s: %w", tier1ID, err)
+	}
+	return &result, nil
+}
+
+// GetTier0LocaleServices lists the locale-services of one T0/VRF, given its
+// Policy path (/infra/tier-0s/<id>).
+func (c *Client) GetTier0LocaleServices(ctx context.Context, tier0Path string) ([]PolicyLocaleServices, error) {
+	var all []PolicyLocaleServices
+	cursor := ""
+	for {
+		path := "/policy/api/v1" + tier0Path + "/locale-services?page_size=100"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page PolicyLocaleServicesList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("locale-services for %s: %w", tier0Path, err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
+// GetBGPNeighborStatus returns the BGP session state per neighbor and edge
+// node under one locale-services path (/infra/tier-0s/<id>/locale-services/<ls>).
+func (c *Client) GetBGPNeighborStatus(ctx context.Context, localeServicesPath string) ([]BGPNeighborStatus, error) {
+	var result BGPNeighborStatusList
+	if err := c.doGet(ctx, "/policy/api/v1"+localeServicesPath+"/bgp/neighbors/status", &result); err != nil {
+		return nil, fmt.Errorf("bgp neighbors status for %s: %w", localeServicesPath, err)
+	}
+	return result.Results, nil
+}
+
 // GetPolicySegments lists all Policy API segments. connectivity_path is the
 // link to either a T1 or T0 — used for "segments per VRF/T0".
 func (c *Client) GetPolicySegments(ctx context.Context) ([]PolicySegment, error) {
@@ -357,6 +423,41 @@ func (c *Client) GetTier1NATRuleCount(ctx context.Context, tier1ID string) (int,
 	return result.ResultCount, nil
 }
 
+// GetStaticRouteCount returns the number of static routes on one gateway,
+// given its Policy path (/infra/tier-1s/<id> or /infra/tier-0s/<id>).
+func (c *Client) GetStaticRouteCount(ctx context.Context, gatewayPath string) (int, error) {
+	var result PolicyStaticRouteList
+	if err := c.doGet(ctx, "/policy/api/v1"+gatewayPath+"/static-routes?page_size=1", &result); err != nil {
+		return 0, fmt.Errorf("static routes count for %s: %w", gatewayPath, err)
+	}
+	return result.ResultCount, nil
+}
+
+// GetRouteTable returns one route table (RoutingTable or ForwardingTable) of
+// a gateway given its Policy path, one entry per edge node. The tables can
+// hold thousands of routes, so callers should keep this on the slow path.
+func (c *Client) GetRouteTable(ctx context.Context, gatewayPath, table string) ([]RouteTableEdge, error) {
+	var all []RouteTableEdge
+	cursor := ""
+	for {
+		path := "/policy/api/v1" + gatewayPath + "/" + table +
+			"?enforcement_point_path=" + url.QueryEscape("/infra/sites/default/enforcement-points/default")
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page RouteTableList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("%s for %s: %w", table, gatewayPath, err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
 // GetGatewayPolicies lists all gateway firewall policies with rule_count populated.
 // Used to attribute firewall rules to specific T1/T0 gateways via Scope.
 func (c *Client) GetGatewayPolicies(ctx context.Context) ([]PolicyGatewayPolicy, error) {
@@ -380,11 +481,11 @@ func (c *Client) GetGatewayPolicies(ctx context.Context) ([]PolicyGatewayPolicy,
 	return all, nil
 }
 
-// GetPolicyEdgeClusters lists all Policy API edge clusters under the default
+// listPolicyEdgeClusters lists all Policy API edge clusters under the default
 // site/enforcement-point. nsx_id matches the legacy logical-router.edge_cluster_id
 // — needed to resolve T1.edge_cluster_id → human-readable cluster display_name
 // for the Slack T1-created message ("criado no CLUSTER <name>").
-func (c *Client) GetPolicyEdgeClusters(ctx context.Context) ([]PolicyEdgeCluster, error) {
+func (c *Client) listPolicyEdgeClusters(ctx context.Context) ([]PolicyEdgeCluster, error) {
 	var all []PolicyEdgeCluster
 	cursor := ""
 	for {
@@ -405,6 +506,29 @@ func (c *Client) GetPolicyEdgeClusters(ctx context.Context) ([]PolicyEdgeCluster
 	return all, nil
 }
 
+// GetCertificates lists every certificate in trust management, with the
+// decoded X.509 details.
+func (c *Client) GetCertificates(ctx context.Context) ([]Certificate, error) {
+	var all []Certificate
+	cursor := ""
+	for {
+		path := "/api/v1/trust-management/certificates?details=true&page_size=100"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page CertificateList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("certificates: %w", err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
 // GetPolicyGroups lists all groups in the default domain. Used to count
 // groups with empty Expression (potential orphans).
 func (c *Client) GetPolicyGroups(ctx context.Context) ([]PolicyGroup, error) {
@@ -0,0 +1,87 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Manager backups
+// GET /api/v1/cluster/backups/overview
+// GET /api/v1/cluster/backups/history
+// ---------------------------------------------------------------------------
+
+// BackupOverview is the backup configuration and the operation in progress.
+// The API reports neither the size of a backup nor the remote file listing
+// (that needs the SFTP credentials), so size is not available here.
+type BackupOverview struct {
+	BackupConfig struct {
+		BackupEnabled bool `json:"backup_enabled"`
+	} `json:"backup_config"`
+	CurrentBackupOperationStatus struct {
+		BackupID    string `json:"backup_id"`
+		CurrentStep string `json:"current_step"`
+		StartTime   int64  `json:"start_time"` // epoch ms
+	} `json:"current_backup_operation_status"`
+}
+
+// BackupHistory is the recent backup attempts per backup type.
+type BackupHistory struct {
+	ClusterBackupStatuses   []BackupStatus `json:"cluster_backup_statuses"`
+	NodeBackupStatuses      []BackupStatus `json:"node_backup_statuses"`
+	InventoryBackupStatuses []BackupStatus `json:"inventory_backup_statuses"`
+}
+
+// BackupStatus is one backup attempt.
+type BackupStatus struct {
+	BackupID     string `json:"backup_id"`
+	StartTime    int64  `json:"start_time"` // epoch ms
+	EndTime      int64  `json:"end_time"`   // epoch ms
+	Success      bool   `json:"success"`
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// End is when the attempt finished (its start when end_time is missing).
+func (b *BackupStatus) End() time.Time {
+	if b.EndTime > 0 {
+		return time.UnixMilli(b.EndTime)
+	}
+	return time.UnixMilli(b.StartTime)
+}
+
+// LatestBackups returns the most recent successful and failed attempts in
+// statuses; either is nil when there is none.
+func LatestBackups(statuses []BackupStatus) (success, failure *BackupStatus) {
+	for i := range statuses {
+		b := &statuses[i]
+		if b.Success {
+			if success == nil || b.End().After(success.End()) {
+				success = b
+			}
+		} else if failure == nil || b.End().After(failure.End()) {
+			failure = b
+		}
+	}
+	return success, failure
+}
+
+// GetBackupOverview returns the backup configuration and current operation.
+func (c *Client) GetBackupOverview(ctx context.Context) (*BackupOverview, error) {
+	var result BackupOverview
+	if err := c.doGet(ctx, "/api/v1/cluster/backups/overview", &result); err != nil {
+		return nil, fmt.Errorf("backup overview: %w", err)
+	}
+	return &result, nil
+}
+
+// GetBackupHistory returns the recent cluster, node and inventory backup
+// attempts.
+func (c *Client) GetBackupHistory(ctx context.Context) (*BackupHistory, error) {
+	var result BackupHistory
+	if err := c.doGet(ctx, "/api/v1/cluster/backups/history", &result); err != nil {
+		return nil, fmt.Errorf("backup history: %w", err)
+	}
+	return &result, nil
+}
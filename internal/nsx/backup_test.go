@@ -0,0 +1,22 @@
+package nsx
+
+import "testing"
+
+func TestLatestBackups(t *testing.T) {
+	statuses := []BackupStatus{
+		{BackupID: "a", EndTime: 1000, Success: true},
+		{BackupID: "b", EndTime: 3000, Success: false, ErrorCode: "BACKUP_SERVER_UNREACHABLE"},
+		{BackupID: "c", EndTime: 2000, Success: true},
+		{BackupID: "d", StartTime: 4000, Success: false}, // still no end_time
+	}
+	success, failure := LatestBackups(statuses)
+	if success == nil || success.BackupID != "c" {
+		t.Errorf("success = %+v, want c", success)
+	}
+	if failure == nil || failure.BackupID != "d" {
+		t.Errorf("failure = %+v, want d", failure)
+	}
+	if s, f := LatestBackups(nil); s != nil || f != nil {
+		t.Errorf("LatestBackups(nil) = %v, %v", s, f)
+	}
+}
@@ -0,0 +1,149 @@
+package nsx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nsx-collector/internal/telemetry"
+)
+
+// Cached inventory resources (keys of CacheConfig.TTLs).
+const (
+	ResourceTransportNodes     = "transport_nodes"
+	ResourceLogicalRouters     = "logical_routers"
+	ResourceLogicalRouterPorts = "logical_router_ports"
+	ResourcePolicyTier0s       = "policy_tier0s"
+	ResourcePolicyTier1s       = "policy_tier1s"
+	ResourcePolicyEdgeClusters = "policy_edge_clusters"
+)
+
+// CacheConfig controls the inventory cache in front of the list endpoints.
+// Worker, HACollector and CapacityCollector share one Client per manager, so
+// within a cycle they share one fetch of each inventory list.
+type CacheConfig struct {
+	Enabled bool
+	// TTL applies to resources without an entry in TTLs.
+	TTL time.Duration
+	// TTLs overrides TTL per resource (Resource* constants). 0 disables
+	// caching for that resource (single-flight still applies).
+	TTLs map[string]time.Duration
+}
+
+// inventoryCache holds the last successful result per resource plus the
+// in-flight fetch, so concurrent callers wait for one request instead of
+// each issuing their own. Errors are never cached.
+type inventoryCache struct {
+	mu      sync.Mutex
+	cfg     CacheConfig
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	value   any
+	fetched time.Time
+	// inflight is non-nil while a fetch is running; closed when it ends.
+	inflight chan struct{}
+	err      error
+}
+
+func newInventoryCache(cfg CacheConfig) *inventoryCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Second
+	}
+	return &inventoryCache{cfg: cfg, entries: map[string]*cacheEntry{}}
+}
+
+func (ic *inventoryCache) ttl(resource string) time.Duration {
+	if d, ok := ic.cfg.TTLs[resource]; ok {
+		return d
+	}
+	return ic.cfg.TTL
+}
+
+// cached returns the cached value of resource when fresh, joins an in-flight
+// fetch when one is running, or starts fetch. The shared fetch runs on a
+// context detached from the caller's, bounded by the inventory retry
+// deadline, so one caller giving up (a shorter collector deadline) does not
+// fail the others; each caller still returns early on its own ctx. The
+// returned slice is shared between callers and must not be modified.
+func cached[T any](ctx context.Context, c *Client, resource string, fetch func(context.Context) (T, error)) (T, error) {
+	ic := c.cache
+	if !ic.cfg.Enabled {
+		return fetch(ctx)
+	}
+
+	ic.mu.Lock()
+	e := ic.entries[resource]
+	if e != nil && e.inflight == nil && time.Since(e.fetched) < ic.ttl(resource) {
+		v := e.value.(T)
+		ic.mu.Unlock()
+		telemetry.NSXCacheRequests.WithLabelValues(c.site, resource, "hit").Inc()
+		return v, nil
+	}
+	if e != nil && e.inflight != nil {
+		telemetry.NSXCacheRequests.WithLabelValues(c.site, resource, "shared").Inc()
+	} else {
+		e = &cacheEntry{inflight: make(chan struct{})}
+		ic.entries[resource] = e
+		telemetry.NSXCacheRequests.WithLabelValues(c.site, resource, "miss").Inc()
+		go func() {
+			fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.retry[EndpointInventory].Deadline)
+			defer cancel()
+			v, err := fetch(fctx)
+
+			ic.mu.Lock()
+			e.value, e.err, e.fetched = v, err, time.Now()
+			close(e.inflight)
+			e.inflight = nil
+			if err != nil {
+				delete(ic.entries, resource)
+			}
+			ic.mu.Unlock()
+		}()
+	}
+	wait := e.inflight
+	ic.mu.Unlock()
+
+	select {
+	case <-wait:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	if e.err != nil {
+		var zero T
+		return zero, e.err
+	}
+	return e.value.(T), nil
+}
+
+// GetTransportNodes returns all transport nodes (cached, see CacheConfig).
+func (c *Client) GetTransportNodes(ctx context.Context) ([]TransportNodeItem, error) {
+	return cached(ctx, c, ResourceTransportNodes, c.listTransportNodes)
+}
+
+// GetLogicalRouters returns all logical routers (cached, see CacheConfig).
+func (c *Client) GetLogicalRouters(ctx context.Context) ([]LogicalRouter, error) {
+	return cached(ctx, c, ResourceLogicalRouters, c.listLogicalRouters)
+}
+
+// GetLogicalRouterPorts returns all logical router ports (cached, see CacheConfig).
+func (c *Client) GetLogicalRouterPorts(ctx context.Context) ([]LogicalRouterPort, error) {
+	return cached(ctx, c, ResourceLogicalRouterPorts, c.listLogicalRouterPorts)
+}
+
+// GetPolicyTier0s lists all Policy API Tier-0 gateways (cached, see CacheConfig).
+func (c *Client) GetPolicyTier0s(ctx context.Context) ([]PolicyTier0, error) {
+	return cached(ctx, c, ResourcePolicyTier0s, c.listPolicyTier0s)
+}
+
+// GetPolicyTier1s lists all Policy API Tier-1 gateways (cached, see CacheConfig).
+func (c *Client) GetPolicyTier1s(ctx context.Context) ([]PolicyTier1, error) {
+	return cached(ctx, c, ResourcePolicyTier1s, c.listPolicyTier1s)
+}
+
+// GetPolicyEdgeClusters lists all Policy API edge clusters (cached, see CacheConfig).
+func (c *Client) GetPolicyEdgeClusters(ctx context.Context) ([]PolicyEdgeCluster, error) {
+	return cached(ctx, c, ResourcePolicyEdgeClusters, c.listPolicyEdgeClusters)
+}
@@ -0,0 +1,90 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInventoryCacheSingleFlight(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"results":[{"id":"lr1"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL, Cache: CacheConfig{Enabled: true, TTL: time.Minute}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lrs, err := c.GetLogicalRouters(ctx)
+			if err != nil || len(lrs) != 1 {
+				t.Errorf("GetLogicalRouters = %v, %v", lrs, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if _, err := c.GetLogicalRouters(ctx); err != nil {
+		t.Fatalf("GetLogicalRouters (cached): %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1 (shared + cached)", got)
+	}
+}
+
+func TestInventoryCacheLeaderCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"results":[{"id":"lr1"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL, Cache: CacheConfig{Enabled: true, TTL: time.Minute}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// The caller that starts the fetch gives up; the one sharing it must not.
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := c.GetLogicalRouters(leaderCtx)
+		leaderErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	waiter := make(chan error)
+	go func() {
+		lrs, err := c.GetLogicalRouters(context.Background())
+		if err == nil && len(lrs) != 1 {
+			t.Errorf("GetLogicalRouters = %v", lrs)
+		}
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Fatalf("leader err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-waiter; err != nil {
+		t.Fatalf("waiter err = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package nsx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Trust management — certificates
+// GET /api/v1/trust-management/certificates?details=true
+// ---------------------------------------------------------------------------
+
+// CertificateList represents GET /api/v1/trust-management/certificates.
+type CertificateList struct {
+	ResultCount int           `json:"result_count"`
+	Cursor      string        `json:"cursor"`
+	Results     []Certificate `json:"results"`
+}
+
+// Certificate is one certificate known to the Manager (API/UI, cluster,
+// Corfu, CCP, transport node, imported CAs, ...).
+type Certificate struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	// ResourceType is certificate_self_signed, certificate_signed or
+	// certificate_ca.
+	ResourceType string            `json:"resource_type"`
+	PemEncoded   string            `json:"pem_encoded"`
+	UsedBy       []CertificateUser `json:"used_by"`
+	Details      []CertificateX509 `json:"details"`
+}
+
+// CertificateUser is one node/service pair using a certificate.
+type CertificateUser struct {
+	NodeID       string   `json:"node_id"`
+	ServiceTypes []string `json:"service_types"`
+}
+
+// CertificateX509 is the decoded view NSX returns with details=true, one
+// entry per certificate in the chain (leaf first).
+type CertificateX509 struct {
+	SubjectCN string `json:"subject_cn"`
+	IssuerCN  string `json:"issuer_cn"`
+	Subject   string `json:"subject"`
+	Issuer    string `json:"issuer"`
+	NotAfter  int64  `json:"not_after"` // epoch ms
+}
+
+// CertificateInfo is what the collector needs from a certificate's leaf.
+type CertificateInfo struct {
+	Issuer     string
+	NotAfter   time.Time
+	SelfSigned bool
+}
+
+// Info decodes the leaf certificate. The PEM is authoritative; the details
+// returned by the Manager are the fallback when it does not parse.
+// Self-signed follows the reclassification (IsSelfSignedText on the
+// resource_type), plus issuer == subject on the leaf itself, so a
+// self-signed cert imported as certificate_signed is still recognised.
+func (c *Certificate) Info() (CertificateInfo, error) {
+	selfSigned := IsSelfSignedText(c.ResourceType)
+	if block, _ := pem.Decode([]byte(c.PemEncoded)); block != nil {
+		if leaf, err := x509.ParseCertificate(block.Bytes); err == nil {
+			issuer := leaf.Issuer.CommonName
+			if issuer == "" {
+				issuer = leaf.Issuer.String()
+			}
+			return CertificateInfo{
+				Issuer:     issuer,
+				NotAfter:   leaf.NotAfter,
+				SelfSigned: selfSigned || bytes.Equal(leaf.RawIssuer, leaf.RawSubject),
+			}, nil
+		}
+	}
+	if len(c.Details) == 0 || c.Details[0].NotAfter == 0 {
+		return CertificateInfo{}, errors.New("certificate " + c.ID + ": no parsable pem_encoded or details")
+	}
+	d := c.Details[0]
+	issuer := d.IssuerCN
+	if issuer == "" {
+		issuer = d.Issuer
+	}
+	return CertificateInfo{
+		Issuer:     issuer,
+		NotAfter:   time.UnixMilli(d.NotAfter),
+		SelfSigned: selfSigned || (d.Subject != "" && d.Subject == d.Issuer),
+	}, nil
+}
+
+// Services returns the distinct service types using the certificate, sorted.
+func (c *Certificate) Services() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, u := range c.UsedBy {
+		for _, s := range u.ServiceTypes {
+			if s != "" && !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,89 @@
+package nsx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCertificateInfo(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAfter := time.Date(2027, 3, 1, 12, 0, 0, 0, time.UTC)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "nsx-mgr"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemText := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	t.Run("pem self-signed leaf", func(t *testing.T) {
+		c := &Certificate{ID: "a", ResourceType: "certificate_signed", PemEncoded: pemText}
+		info, err := c.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.SelfSigned || info.Issuer != "nsx-mgr" || !info.NotAfter.Equal(notAfter) {
+			t.Errorf("Info() = %+v", info)
+		}
+	})
+
+	t.Run("details fallback", func(t *testing.T) {
+		c := &Certificate{
+			ID:           "b",
+			ResourceType: "certificate_signed",
+			Details: []CertificateX509{{
+				IssuerCN: "Corp CA", Subject: "CN=api", Issuer: "CN=Corp CA",
+				NotAfter: notAfter.UnixMilli(),
+			}},
+		}
+		info, err := c.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.SelfSigned || info.Issuer != "Corp CA" || !info.NotAfter.Equal(notAfter) {
+			t.Errorf("Info() = %+v", info)
+		}
+	})
+
+	t.Run("resource type marks self-signed", func(t *testing.T) {
+		c := &Certificate{
+			ID:           "c",
+			ResourceType: "certificate_self_signed",
+			Details:      []CertificateX509{{NotAfter: notAfter.UnixMilli()}},
+		}
+		if info, err := c.Info(); err != nil || !info.SelfSigned {
+			t.Errorf("Info() = %+v, %v; want self-signed", info, err)
+		}
+	})
+
+	t.Run("nothing to decode", func(t *testing.T) {
+		if _, err := (&Certificate{ID: "d"}).Info(); err == nil {
+			t.Error("Info() succeeded without pem or details")
+		}
+	})
+}
+
+func TestCertificateServices(t *testing.T) {
+	c := &Certificate{UsedBy: []CertificateUser{
+		{NodeID: "n1", ServiceTypes: []string{"MGMT_CLUSTER", "API"}},
+		{NodeID: "n2", ServiceTypes: []string{"API"}},
+	}}
+	if got, want := c.Services(), []string{"API", "MGMT_CLUSTER"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Services() = %v, want %v", got, want)
+	}
+}
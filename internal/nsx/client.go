@@ -1,6 +1,7 @@
 package nsx
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -9,76 +10,244 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"nsx-collector/internal/telemetry"
+)
+
+// Auth modes accepted in managers.yaml (auth_mode).
+const (
+	// AuthBasic sends HTTP Basic credentials on every request (the historic
+	// behaviour). Each request costs the Manager a full password check.
+	AuthBasic = "basic"
+	// AuthSession logs in once via /api/session/create and reuses the
+	// JSESSIONID cookie + X-XSRF-TOKEN until the Manager rejects it.
+	AuthSession = "session"
 )
 
+// Options holds the per-manager connection settings used to build a Client.
+type Options struct {
+	// Site labels the limiter telemetry (one budget per manager).
+	Site     string
+	BaseURL  string
+	Username string
+	Password string
+	// TLS controls Manager certificate verification and the optional
+	// client certificate.
+	TLS TLSOptions
+	// AuthMode is AuthBasic (default when empty) or AuthSession.
+	AuthMode string
+	// RateLimit is the shared request budget for this Manager.
+	RateLimit LimiterConfig
+	// Retry is the default retry policy; RetryOverrides replaces fields of it
+	// per endpoint class (EndpointStatus, EndpointInventory).
+	Retry          RetryPolicy
+	RetryOverrides map[string]RetryPolicy
+	// Failover enables talking to individual Manager nodes when the VIP
+	// (BaseURL) is unhealthy.
+	Failover FailoverConfig
+	// Cache fronts the inventory list endpoints (see CacheConfig).
+	Cache CacheConfig
+}
+
 // Client is an authenticated NSX Manager API client.
 type Client struct {
-	baseURL  string
+	site     string
+	nodes    *endpoints
 	username string
 	password string
+	authMode string
 	http     *http.Client
+	tls      *tls.Config
+	session  session
+	limiter  *Limiter
+	retry    map[string]RetryPolicy
+	cache    *inventoryCache
+	caps     capabilities
 }
 
-// NewClient creates a new NSX API client.
-func NewClient(baseURL, username, password string, tlsSkipVerify bool) *Client {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: tlsSkipVerify}, //nolint:gosec
+// NewClient creates a new NSX API client. It fails when the TLS material
+// (ca_file, client certificate, fingerprints) cannot be loaded.
+func NewClient(opts Options) (*Client, error) {
+	tlsCfg, err := buildTLSConfig(opts.Site, opts.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("manager %s: %w", opts.Site, err)
+	}
+	transport := &http.Transport{TLSClientConfig: tlsCfg}
+	authMode := opts.AuthMode
+	if authMode == "" {
+		authMode = AuthBasic
 	}
 	return &Client{
-		baseURL:  baseURL,
-		username: username,
-		password: password,
+		site:     opts.Site,
+		nodes:    newEndpoints(opts.BaseURL, opts.Failover),
+		username: opts.Username,
+		password: opts.Password,
+		authMode: authMode,
 		http: &http.Client{
 			Timeout:   15 * time.Second,
 			Transport: transport,
 		},
+		tls:     tlsCfg,
+		limiter: NewLimiter(opts.RateLimit),
+		retry:   buildRetryPolicies(opts.Retry, opts.RetryOverrides),
+		cache:   newInventoryCache(opts.Cache),
+	}, nil
+}
+
+// MaxInFlight returns the concurrency cap of the shared limiter, for callers
+// that fan out one request per object (NAT per T1, HA status per T1).
+func (c *Client) MaxInFlight() int { return c.limiter.MaxInFlight() }
+
+// acquire takes a slot from the shared limiter and records queue telemetry.
+// The in-flight gauge is refreshed on both take and release.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	release, wait, err := c.limiter.Acquire(ctx)
+	telemetry.NSXQueueWait.WithLabelValues(c.site).Observe(wait.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	inFlight := telemetry.NSXRequestsInFlight.WithLabelValues(c.site)
+	inFlight.Set(float64(c.limiter.InFlight()))
+	return func() {
+		release()
+		inFlight.Set(float64(c.limiter.InFlight()))
+	}, nil
+}
+
+// observe feeds one response back into the limiter and refreshes the budget gauge.
+func (c *Client) observe(status int, latency time.Duration) {
+	c.limiter.Observe(status, latency)
+	if status == http.StatusTooManyRequests {
+		telemetry.NSXThrottled.WithLabelValues(c.site).Inc()
 	}
+	telemetry.NSXRequestBudget.WithLabelValues(c.site).Set(c.limiter.Rate())
 }
 
 // doGet performs an authenticated GET request and decodes the JSON response.
-// Retries on HTTP 429 with exponential backoff (honoring Retry-After when present)
-// since the NSX Manager throttles bursts of requests.
+// Transient failures (retryable statuses, connection resets, timeouts) are
+// retried per the RetryPolicy of the endpoint class, with jittered
+// exponential backoff (honoring Retry-After when present) inside the policy's
+// total deadline. When failover is enabled and the endpoint itself looks
+// down, the next attempt goes to a healthy manager node without waiting.
+// Every attempt goes through the shared limiter.
 func (c *Client) doGet(ctx context.Context, path string, dest interface{}) error {
-	const maxAttempts = 4
-	backoff := 500 * time.Millisecond
+	return c.do(ctx, http.MethodGet, path, nil, dest)
+}
+
+// doPost sends body as JSON and decodes the JSON response, with the same
+// retry/failover/limiter handling as doGet. Only used for read-only POST
+// endpoints (search/aggregate), so retrying is safe.
+func (c *Client) doPost(ctx context.Context, path string, body, dest interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, path, data, dest)
+}
+
+// do runs one logical request through the retry policy of its endpoint class.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, dest interface{}) error {
+	policy := c.retry[endpointClass(path)]
+	ctx, cancel := context.WithTimeout(ctx, policy.Deadline)
+	defer cancel()
+	backoff := policy.BaseBackoff
+	c.maybeFailback(ctx)
 
 	for attempt := 1; ; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		base, err := c.sendOnce(ctx, method, path, body, dest)
+		if err == nil {
+			c.traceServed(ctx, base)
+			return nil
+		}
+		switched := shouldFailover(err) && c.failover(ctx, base)
+		wait, retryable := policy.retryDelay(err)
+		if !retryable || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if switched {
+			wait = 0 // fresh endpoint: no point waiting
+		} else if wait <= 0 {
+			wait = jitter(backoff)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+		telemetry.NSXRetries.WithLabelValues(c.site, normalizeEndpoint(path)).Inc()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// sendOnce performs a single request (plus, in session mode, one transparent
+// re-login when the Manager answers 401/403 on a stale session). It returns
+// the base URL that was used, for failover.
+func (c *Client) sendOnce(ctx context.Context, method, path string, body []byte, dest interface{}) (string, error) {
+	reauthenticated := false
+	for {
+		base := c.baseURL()
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, base+path, reqBody)
+		if err != nil {
+			return base, fmt.Errorf("creating request: %w", err)
+		}
+		gen, err := c.authorize(ctx, req, base)
 		if err != nil {
-			return fmt.Errorf("creating request: %w", err)
+			return base, err
 		}
-		req.SetBasicAuth(c.username, c.password)
 		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
 
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return base, err
+		}
+		sent := time.Now()
 		resp, err := c.http.Do(req)
 		if err != nil {
-			return fmt.Errorf("executing request: %w", err)
+			release()
+			c.observe(0, time.Since(sent))
+			return base, fmt.Errorf("executing request: %w", err)
 		}
+		c.observe(resp.StatusCode, time.Since(sent))
 
-		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxAttempts {
-			wait := parseRetryAfter(resp.Header.Get("Retry-After"), backoff)
+		if c.authMode == AuthSession && !reauthenticated &&
+			(resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			// Session expired or was invalidated on the Manager side (restart,
+			// idle timeout). Drop it and log in again once.
 			io.Copy(io.Discard, resp.Body)
 			resp.Body.Close()
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(wait):
-			}
-			backoff *= 2
+			release()
+			c.invalidateSession(gen)
+			reauthenticated = true
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
+			apiErr := newAPIError(resp, path)
 			resp.Body.Close()
-			return fmt.Errorf("unexpected status %d for %s", resp.StatusCode, path)
+			release()
+			return base, apiErr
 		}
 
 		err = json.NewDecoder(resp.Body).Decode(dest)
 		resp.Body.Close()
+		release()
 		if err != nil {
-			return fmt.Errorf("decoding response: %w", err)
+			return base, fmt.Errorf("decoding response: %w", err)
 		}
-		return nil
+		return base, nil
 	}
 }
 
@@ -0,0 +1,79 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSessionAuth(t *testing.T) {
+	var logins, destroys atomic.Int32
+	var expireNext atomic.Bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/session/create":
+			if r.FormValue("j_username") != "admin" || r.FormValue("j_password") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			n := logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "sess-" + string(rune('0'+n))})
+			w.Header().Set("X-XSRF-TOKEN", "xsrf")
+		case "/api/session/destroy":
+			destroys.Add(1)
+		case "/api/v1/cluster/status":
+			if _, err := r.Cookie("JSESSIONID"); err != nil || r.Header.Get("X-XSRF-TOKEN") != "xsrf" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if _, _, ok := r.BasicAuth(); ok {
+				t.Errorf("basic credentials sent in session mode")
+			}
+			if expireNext.CompareAndSwap(true, false) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"cluster_id":"c1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL, Username: "admin", Password: "secret", AuthMode: AuthSession})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		cs, err := c.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("GetClusterStatus: %v", err)
+		}
+		if cs.ClusterID != "c1" {
+			t.Fatalf("cluster_id = %q, want c1", cs.ClusterID)
+		}
+	}
+	if got := logins.Load(); got != 1 {
+		t.Fatalf("logins = %d, want 1 (session must be reused)", got)
+	}
+
+	expireNext.Store(true)
+	if _, err := c.GetClusterStatus(ctx); err != nil {
+		t.Fatalf("GetClusterStatus after expiry: %v", err)
+	}
+	if got := logins.Load(); got != 2 {
+		t.Fatalf("logins = %d, want 2 (re-login on 401)", got)
+	}
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := destroys.Load(); got != 1 {
+		t.Fatalf("destroys = %d, want 1", got)
+	}
+}
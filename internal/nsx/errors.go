@@ -0,0 +1,112 @@
+package nsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// APIError is returned by the client when the Manager answers with a non-2xx
+// status. It carries enough detail for callers to tell a deleted object (404)
+// from a Manager in trouble (5xx) or a throttled request (429), which the
+// HA watchlist healing and the t1watch/capacity collectors rely on to only
+// forget objects that are really gone.
+type APIError struct {
+	StatusCode int
+	// ErrorCode / ErrorMessage come from the NSX error body
+	// ({"error_code": 202, "error_message": "..."}), when present.
+	ErrorCode    int
+	ErrorMessage string
+	// Endpoint is the request path (without host).
+	Endpoint string
+	// RequestID is the Manager's X-NSX-REQUESTID header, useful when
+	// correlating with /var/log/proton/nsxapi.log on the Manager.
+	RequestID string
+
+	// retryAfter is the Retry-After hint of a 429/503, for the retry loop.
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("unexpected status %d for %s", e.StatusCode, e.Endpoint)
+	if e.ErrorCode != 0 || e.ErrorMessage != "" {
+		msg += fmt.Sprintf(": nsx error %d: %s", e.ErrorCode, e.ErrorMessage)
+	}
+	if e.RequestID != "" {
+		msg += " (request_id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from a non-2xx response. The body is read
+// (bounded) to extract the NSX error fields; decoding failures are ignored.
+func newAPIError(resp *http.Response, endpoint string) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		RequestID:  resp.Header.Get("X-NSX-REQUESTID"),
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), 0),
+	}
+	var body struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err == nil && len(data) > 0 {
+		if json.Unmarshal(data, &body) == nil {
+			e.ErrorCode = body.ErrorCode
+			e.ErrorMessage = body.ErrorMessage
+		}
+	}
+	return e
+}
+
+// statusOf returns the HTTP status of an APIError anywhere in err's chain, or 0.
+func statusOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an NSX 404 — the object does not exist.
+func IsNotFound(err error) bool { return statusOf(err) == http.StatusNotFound }
+
+// IsThrottled reports whether err is an NSX 429 that survived the client's retries.
+func IsThrottled(err error) bool { return statusOf(err) == http.StatusTooManyRequests }
+
+// IsAuth reports whether err is an authentication/authorization failure (401/403).
+func IsAuth(err error) bool {
+	s := statusOf(err)
+	return s == http.StatusUnauthorized || s == http.StatusForbidden
+}
+
+// IsServerError reports whether err is a 5xx from the Manager.
+func IsServerError(err error) bool { return statusOf(err) >= 500 }
+
+// IsUnsupported reports whether err says the Manager does not implement the
+// endpoint (404/501 on an API older than the call). A 400 is not enough: it
+// is as likely to be about the query as about the endpoint. Callers mark the
+// matching Capability and fall back.
+func IsUnsupported(err error) bool {
+	switch statusOf(err) {
+	case http.StatusNotFound, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// IsTimeout reports whether err is a request timeout (client deadline or a
+// network-level timeout) rather than an answer from the Manager.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
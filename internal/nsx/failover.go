@@ -0,0 +1,277 @@
+package nsx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nsx-collector/internal/telemetry"
+)
+
+// NodeVIP is the ActiveNode value while requests go through the cluster VIP
+// (the manager's configured URL).
+const NodeVIP = "vip"
+
+// FailoverConfig lets the client bypass the cluster VIP and talk to
+// individual Manager nodes when the VIP stops answering.
+type FailoverConfig struct {
+	Enabled bool
+	// NodeURLs are explicit node base URLs (https://10.0.0.11), tried in order.
+	NodeURLs []string
+	// Discover adds the nodes' mgmt_cluster_listen_ip_address from
+	// /api/v1/cluster/status (see LearnNodes).
+	Discover bool
+	// FailbackInterval is how often the VIP is re-probed while on a node.
+	FailbackInterval time.Duration
+}
+
+// endpoints is the ordered set of base URLs the client may use: the VIP
+// first, then the manager nodes. active indexes the one in use.
+type endpoints struct {
+	mu           sync.Mutex
+	cfg          FailoverConfig
+	urls         []string
+	active       int
+	lastFailback time.Time
+}
+
+func newEndpoints(vip string, cfg FailoverConfig) *endpoints {
+	if cfg.FailbackInterval <= 0 {
+		cfg.FailbackInterval = 5 * time.Minute
+	}
+	e := &endpoints{cfg: cfg, urls: []string{vip}}
+	for _, u := range cfg.NodeURLs {
+		e.add(u)
+	}
+	return e
+}
+
+// add appends u unless already known. Caller holds mu (or owns e).
+func (e *endpoints) add(u string) {
+	for _, known := range e.urls {
+		if known == u {
+			return
+		}
+	}
+	e.urls = append(e.urls, u)
+}
+
+// baseURL returns the base URL requests should currently use.
+func (c *Client) baseURL() string {
+	c.nodes.mu.Lock()
+	defer c.nodes.mu.Unlock()
+	return c.nodes.urls[c.nodes.active]
+}
+
+// ActiveNode identifies which endpoint is serving requests: NodeVIP, or the
+// host of the manager node the client failed over to.
+func (c *Client) ActiveNode() string {
+	return c.nodeName(c.baseURL())
+}
+
+// nodeName maps a base URL to its nsx_node value: NodeVIP for the configured
+// URL, otherwise the node's host.
+func (c *Client) nodeName(base string) string {
+	c.nodes.mu.Lock()
+	vip := c.nodes.urls[0]
+	c.nodes.mu.Unlock()
+	if base == vip {
+		return NodeVIP
+	}
+	if u, err := url.Parse(base); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return base
+}
+
+type nodeTraceKey struct{}
+
+// NodeTrace records which endpoint served the requests made with the
+// context returned by WithNodeTrace. Points are tagged with it (nsx_node)
+// when failover is enabled, so data fetched before a mid-cycle switch keeps
+// the endpoint it actually came from.
+type NodeTrace struct {
+	mu   sync.Mutex
+	node string
+}
+
+// WithNodeTrace returns a context whose requests report the endpoint that
+// served them to the returned NodeTrace.
+func WithNodeTrace(ctx context.Context) (context.Context, *NodeTrace) {
+	t := &NodeTrace{}
+	return context.WithValue(ctx, nodeTraceKey{}, t), t
+}
+
+// Node returns the endpoint (NodeVIP or a node host) that served the last
+// successful traced request, or "" when none reached the Manager (failures,
+// cache hits).
+func (t *NodeTrace) Node() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.node
+}
+
+// traceServed records base as the endpoint that served a request made
+// with ctx, when ctx carries a NodeTrace.
+func (c *Client) traceServed(ctx context.Context, base string) {
+	t, ok := ctx.Value(nodeTraceKey{}).(*NodeTrace)
+	if !ok {
+		return
+	}
+	node := c.nodeName(base)
+	t.mu.Lock()
+	t.node = node
+	t.mu.Unlock()
+}
+
+// FailoverEnabled reports whether node failover is configured.
+func (c *Client) FailoverEnabled() bool { return c.nodes.cfg.Enabled }
+
+// LearnNodes adds the manager nodes listed in cluster status as failover
+// candidates, reusing the VIP URL's scheme and port. No-op unless failover
+// and discovery are enabled.
+func (c *Client) LearnNodes(cs *ClusterStatus) {
+	if !c.nodes.cfg.Enabled || !c.nodes.cfg.Discover || cs == nil {
+		return
+	}
+	c.nodes.mu.Lock()
+	defer c.nodes.mu.Unlock()
+	vip, err := url.Parse(c.nodes.urls[0])
+	if err != nil {
+		return
+	}
+	nodes := append(append([]ClusterNode{}, cs.MgmtClusterStatus.OnlineNodes...), cs.MgmtClusterStatus.OfflineNodes...)
+	for _, n := range nodes {
+		if n.MgmtClusterListenIPAddress == "" {
+			continue
+		}
+		u := *vip
+		u.Host = n.MgmtClusterListenIPAddress
+		if port := vip.Port(); port != "" {
+			u.Host += ":" + port
+		}
+		c.nodes.add(u.String())
+	}
+}
+
+// failover moves the client off failed (the base URL whose request just
+// failed) to the first other endpoint that passes a health probe. Returns
+// true when the active endpoint changed. The probes run without c.nodes.mu
+// held, so other requests are not stalled behind them; concurrent callers
+// that saw the same failure switch only once.
+func (c *Client) failover(ctx context.Context, failed string) bool {
+	if !c.nodes.cfg.Enabled {
+		return false
+	}
+	c.nodes.mu.Lock()
+	if c.nodes.urls[c.nodes.active] != failed {
+		c.nodes.mu.Unlock()
+		return true // someone else already moved us
+	}
+	urls := append([]string(nil), c.nodes.urls...)
+	active := c.nodes.active
+	c.nodes.mu.Unlock()
+
+	for i, u := range urls {
+		if i == active || !c.probe(ctx, u) {
+			continue
+		}
+		c.nodes.mu.Lock()
+		defer c.nodes.mu.Unlock()
+		if c.nodes.urls[c.nodes.active] == failed {
+			c.switchTo(i, "failover")
+		}
+		return true
+	}
+	return false
+}
+
+// maybeFailback re-probes the VIP every FailbackInterval while a node is
+// serving, and returns to it once healthy. The probe runs without
+// c.nodes.mu held.
+func (c *Client) maybeFailback(ctx context.Context) {
+	if !c.nodes.cfg.Enabled {
+		return
+	}
+	c.nodes.mu.Lock()
+	if c.nodes.active == 0 || time.Since(c.nodes.lastFailback) < c.nodes.cfg.FailbackInterval {
+		c.nodes.mu.Unlock()
+		return
+	}
+	c.nodes.lastFailback = time.Now()
+	vip, serving := c.nodes.urls[0], c.nodes.urls[c.nodes.active]
+	c.nodes.mu.Unlock()
+
+	if !c.probe(ctx, vip) {
+		return
+	}
+	c.nodes.mu.Lock()
+	defer c.nodes.mu.Unlock()
+	if c.nodes.active != 0 && c.nodes.urls[c.nodes.active] == serving {
+		c.switchTo(0, "failback")
+	}
+}
+
+// switchTo makes urls[i] active. The session is bound to the node that
+// issued it, so authorize logs in again on the next request. Caller holds
+// c.nodes.mu.
+func (c *Client) switchTo(i int, reason string) {
+	from := c.nodes.urls[c.nodes.active]
+	c.nodes.active = i
+	c.nodes.lastFailback = time.Now()
+	telemetry.NSXFailovers.WithLabelValues(c.site, reason).Inc()
+	zap.L().Named(c.site).Warn("nsx endpoint switched",
+		zap.String("reason", reason),
+		zap.String("from", from),
+		zap.String("to", c.nodes.urls[i]),
+	)
+}
+
+// probe checks that base answers, authenticating the way requests do. In
+// basic mode it GETs /api/v1/node/version; in session mode it logs in via
+// /api/session/create and, on success, keeps that session for the requests
+// that follow the switch. Any non-5xx status counts as alive: a 401/403
+// still proves the node's API is up.
+func (c *Client) probe(ctx context.Context, base string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if c.authMode == AuthSession {
+		cookie, xsrf, err := c.createSession(ctx, base)
+		if err != nil {
+			status := statusOf(err)
+			return status != 0 && status < 500
+		}
+		c.adoptSession(base, cookie, xsrf)
+		return true
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/api/v1/node/version", nil)
+	if err != nil {
+		return false
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode < 500
+}
+
+// shouldFailover reports whether err means the endpoint itself is unhealthy
+// (transport failure or a gateway-level 5xx) rather than a bad request.
+func shouldFailover(err error) bool {
+	switch statusOf(err) {
+	case 0:
+		return isTransientTransport(err)
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailoverToNode(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cluster_id":"c1"}`))
+	}))
+	defer node.Close()
+	vip := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer vip.Close()
+
+	c, err := NewClient(Options{
+		BaseURL:  vip.URL,
+		Retry:    RetryPolicy{BaseBackoff: time.Millisecond},
+		Failover: FailoverConfig{Enabled: true, NodeURLs: []string{node.URL}},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if got := c.ActiveNode(); got != NodeVIP {
+		t.Fatalf("ActiveNode = %q, want %q", got, NodeVIP)
+	}
+	cs, err := c.GetClusterStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetClusterStatus: %v", err)
+	}
+	if cs.ClusterID != "c1" {
+		t.Fatalf("cluster_id = %q, want c1", cs.ClusterID)
+	}
+	if got := c.ActiveNode(); got != strings.TrimPrefix(node.URL, "http://") {
+		t.Fatalf("ActiveNode = %q, want node host", got)
+	}
+}
+
+func TestLearnNodes(t *testing.T) {
+	c, err := NewClient(Options{
+		BaseURL:  "https://nsx-vip.example:8443",
+		Failover: FailoverConfig{Enabled: true, Discover: true},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cs := &ClusterStatus{}
+	cs.MgmtClusterStatus.OnlineNodes = []ClusterNode{{UUID: "a", MgmtClusterListenIPAddress: "10.0.0.11"}}
+	cs.MgmtClusterStatus.OfflineNodes = []ClusterNode{{UUID: "b", MgmtClusterListenIPAddress: "10.0.0.12"}}
+	c.LearnNodes(cs)
+	c.LearnNodes(cs)
+
+	want := []string{"https://nsx-vip.example:8443", "https://10.0.0.11:8443", "https://10.0.0.12:8443"}
+	if len(c.nodes.urls) != len(want) {
+		t.Fatalf("urls = %v, want %v", c.nodes.urls, want)
+	}
+	for i := range want {
+		if c.nodes.urls[i] != want[i] {
+			t.Fatalf("urls = %v, want %v", c.nodes.urls, want)
+		}
+	}
+}
+
+func TestFailoverProbeDoesNotHoldLock(t *testing.T) {
+	release := make(chan struct{})
+	probing := make(chan struct{}, 1)
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probing <- struct{}{}
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer node.Close()
+	defer close(release)
+
+	c, err := NewClient(Options{
+		BaseURL:  "http://vip.invalid",
+		Failover: FailoverConfig{Enabled: true, NodeURLs: []string{node.URL}},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	go c.failover(context.Background(), "http://vip.invalid")
+	<-probing
+
+	done := make(chan string)
+	go func() { done <- c.baseURL() }()
+	select {
+	case got := <-done:
+		if got != "http://vip.invalid" {
+			t.Fatalf("baseURL = %q during probe", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("baseURL blocked while a failover probe was running")
+	}
+}
+
+func TestNodeTraceRecordsServingEndpoint(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cluster_id":"c1"}`))
+	}))
+	defer node.Close()
+	var vipDown atomic.Bool
+	vip := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if vipDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"cluster_id":"c1"}`))
+	}))
+	defer vip.Close()
+
+	c, err := NewClient(Options{
+		BaseURL:  vip.URL,
+		Retry:    RetryPolicy{BaseBackoff: time.Millisecond},
+		Failover: FailoverConfig{Enabled: true, NodeURLs: []string{node.URL}},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	before, beforeTrace := WithNodeTrace(context.Background())
+	if _, err := c.GetClusterStatus(before); err != nil {
+		t.Fatalf("GetClusterStatus: %v", err)
+	}
+	vipDown.Store(true)
+	after, afterTrace := WithNodeTrace(context.Background())
+	if _, err := c.GetClusterStatus(after); err != nil {
+		t.Fatalf("GetClusterStatus after failover: %v", err)
+	}
+
+	if got := beforeTrace.Node(); got != NodeVIP {
+		t.Fatalf("trace before failover = %q, want %q", got, NodeVIP)
+	}
+	if got, want := afterTrace.Node(), strings.TrimPrefix(node.URL, "http://"); got != want {
+		t.Fatalf("trace after failover = %q, want %q", got, want)
+	}
+}
+
+func TestFailoverProbeUsesSession(t *testing.T) {
+	var logins atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Errorf("basic credentials sent to %s in session mode", r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/api/session/create":
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "node"})
+			w.Header().Set("X-XSRF-TOKEN", "xsrf")
+		case "/api/v1/cluster/status":
+			if ck, err := r.Cookie("JSESSIONID"); err != nil || ck.Value != "node" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"cluster_id":"c1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer node.Close()
+	vip := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer vip.Close()
+
+	c, err := NewClient(Options{
+		BaseURL:  vip.URL,
+		AuthMode: AuthSession,
+		Retry:    RetryPolicy{BaseBackoff: time.Millisecond},
+		Failover: FailoverConfig{Enabled: true, NodeURLs: []string{node.URL}},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetClusterStatus(context.Background()); err != nil {
+		t.Fatalf("GetClusterStatus: %v", err)
+	}
+	if got := logins.Load(); got != 1 {
+		t.Fatalf("node logins = %d, want 1 (probe session reused after the switch)", got)
+	}
+}
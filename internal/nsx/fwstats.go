@@ -0,0 +1,49 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+)
+
+// ---------------------------------------------------------------------------
+// Gateway firewall rule statistics
+// GET /policy/api/v1/infra/domains/default/gateway-policies/<id>/statistics
+// ---------------------------------------------------------------------------
+
+// GatewayPolicyStatisticsList represents the statistics of one gateway
+// policy, one entry per enforcement point.
+type GatewayPolicyStatisticsList struct {
+	Results []struct {
+		Statistics struct {
+			Results []RuleStatistics `json:"results"`
+		} `json:"statistics"`
+	} `json:"results"`
+}
+
+// RuleStatistics is the cumulative traffic matched by one rule on one
+// gateway. Counters reset when the rule is republished or the edge reboots.
+type RuleStatistics struct {
+	Rule           string `json:"rule"`    // rule path
+	LRPath         string `json:"lr_path"` // gateway the counters belong to
+	InternalRuleID string `json:"internal_rule_id"`
+	HitCount       int64  `json:"hit_count"`
+	PacketCount    int64  `json:"packet_count"`
+	ByteCount      int64  `json:"byte_count"`
+	SessionCount   int64  `json:"session_count"`
+}
+
+// GetGatewayPolicyStatistics returns the per-rule statistics of one gateway
+// policy in the default domain.
+func (c *Client) GetGatewayPolicyStatistics(ctx context.Context, policyID string) ([]RuleStatistics, error) {
+	var result GatewayPolicyStatisticsList
+	path := "/policy/api/v1/infra/domains/default/gateway-policies/" + policyID +
+		"/statistics?enforcement_point_path=/infra/sites/default/enforcement-points/default"
+	if err := c.doGet(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("gateway policy %s statistics: %w", policyID, err)
+	}
+	var all []RuleStatistics
+	for _, r := range result.Results {
+		all = append(all, r.Statistics.Results...)
+	}
+	return all, nil
+}
@@ -0,0 +1,106 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Group effective members
+// GET /policy/api/v1/infra/domains/default/groups/<id>/members/<kind>
+// ---------------------------------------------------------------------------
+
+// GroupMemberKinds are the effective member types counted per group, as the
+// last path segment of the members endpoint.
+var GroupMemberKinds = []string{"virtual-machines", "ip-addresses", "segments", "segment-ports"}
+
+type groupMemberList struct {
+	ResultCount int `json:"result_count"`
+}
+
+// GetGroupMemberCount returns how many effective members of kind (one of
+// GroupMemberKinds) group groupID has, in a single call (page_size=1 still
+// populates result_count). ip-addresses counts entries, so a CIDR or range
+// counts once.
+func (c *Client) GetGroupMemberCount(ctx context.Context, groupID, kind string) (int, error) {
+	path := "/policy/api/v1/infra/domains/default/groups/" + groupID + "/members/" + kind + "?page_size=1"
+	var page groupMemberList
+	if err := c.doGet(ctx, path, &page); err != nil {
+		return 0, fmt.Errorf("group %s %s: %w", groupID, kind, err)
+	}
+	return page.ResultCount, nil
+}
+
+// ---------------------------------------------------------------------------
+// DFW rule → group references (Policy search)
+// GET /policy/api/v1/search/query?query=resource_type:Rule
+// ---------------------------------------------------------------------------
+
+// RuleGroupRefs is the group references of one firewall rule.
+type RuleGroupRefs struct {
+	Path              string   `json:"path"`
+	ParentPath        string   `json:"parent_path"`
+	SourceGroups      []string `json:"source_groups"`
+	DestinationGroups []string `json:"destination_groups"`
+	Scope             []string `json:"scope"`
+}
+
+// Groups returns the distinct group paths the rule references in its
+// sources, destinations and applied-to, "ANY" excluded.
+func (r *RuleGroupRefs) Groups() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, list := range [][]string{r.SourceGroups, r.DestinationGroups, r.Scope} {
+		for _, g := range list {
+			if g == "" || strings.EqualFold(g, "ANY") || seen[g] || !strings.Contains(g, "/groups/") {
+				continue
+			}
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+type ruleGroupRefsList struct {
+	Cursor  string          `json:"cursor"`
+	Results []RuleGroupRefs `json:"results"`
+}
+
+// CountDFWRuleGroupRefs returns, per group path, how many distributed
+// firewall rules reference it. Rules are read through the Policy search API
+// (a few paginated calls, at most maxSearchPages); gateway firewall rules
+// are left out.
+func (c *Client) CountDFWRuleGroupRefs(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages == maxSearchPages {
+			return nil, fmt.Errorf("search dfw rules: more than %d pages, giving up", maxSearchPages)
+		}
+		path := "/policy/api/v1/search/query?query=" + url.QueryEscape("resource_type:Rule") +
+			"&page_size=1000&included_fields=path,parent_path,source_groups,destination_groups,scope"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page ruleGroupRefsList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("search dfw rules: %w", err)
+		}
+		for i := range page.Results {
+			r := &page.Results[i]
+			if !strings.Contains(r.ParentPath, "/security-policies/") {
+				continue
+			}
+			for _, g := range r.Groups() {
+				counts[g]++
+			}
+		}
+		if page.Cursor == "" || len(page.Results) == 0 {
+			return counts, nil
+		}
+		cursor = page.Cursor
+	}
+}
@@ -0,0 +1,196 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Policy API — IP pools, pool subnets and IP blocks
+// GET /policy/api/v1/infra/ip-pools
+// GET /policy/api/v1/infra/ip-pools/<id>/ip-subnets
+// GET /policy/api/v1/infra/ip-blocks
+// ---------------------------------------------------------------------------
+
+// IPCount is an address count. IPv6 pools can report more addresses than
+// fit in an int64, as a number or a string; those saturate at MaxInt64.
+type IPCount int64
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (n *IPCount) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("ip count %q: not an integer", s)
+	}
+	*n = IPCount(saturateInt64(v))
+	return nil
+}
+
+// Add returns n+m, saturating at MaxInt64 (MinInt64) like the counts
+// themselves, so summing several huge IPv6 subnets cannot wrap negative.
+func (n IPCount) Add(m IPCount) IPCount {
+	switch {
+	case m > 0 && n > math.MaxInt64-m:
+		return math.MaxInt64
+	case m < 0 && n < math.MinInt64-m:
+		return math.MinInt64
+	}
+	return n + m
+}
+
+func saturateInt64(v *big.Int) int64 {
+	if v.IsInt64() {
+		return v.Int64()
+	}
+	if v.Sign() < 0 {
+		return math.MinInt64
+	}
+	return math.MaxInt64
+}
+
+// IPPoolList represents one page of GET ip-pools.
+type IPPoolList struct {
+	ResultCount int      `json:"result_count"`
+	Cursor      string   `json:"cursor"`
+	Results     []IPPool `json:"results"`
+}
+
+// IPPool is one Policy IP address pool with its usage.
+type IPPool struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Path        string `json:"path"`
+	PoolUsage   struct {
+		TotalIPs               IPCount `json:"total_ips"`
+		AllocatedIPAllocations IPCount `json:"allocated_ip_allocations"`
+		AvailableIPs           IPCount `json:"available_ips"`
+		RequestedIPAllocations IPCount `json:"requested_ip_allocations"`
+	} `json:"pool_usage"`
+}
+
+// IPPoolSubnetList represents one page of GET ip-pools/<id>/ip-subnets.
+type IPPoolSubnetList struct {
+	ResultCount int            `json:"result_count"`
+	Cursor      string         `json:"cursor"`
+	Results     []IPPoolSubnet `json:"results"`
+}
+
+// IPPoolSubnet is one subnet of a pool: carved from an IP block
+// (IpAddressPoolBlockSubnet, IPBlockPath + Size) or static
+// (IpAddressPoolStaticSubnet, CIDR).
+type IPPoolSubnet struct {
+	ID           string  `json:"id"`
+	ResourceType string  `json:"resource_type"`
+	IPBlockPath  string  `json:"ip_block_path,omitempty"`
+	Size         IPCount `json:"size,omitempty"`
+	CIDR         string  `json:"cidr,omitempty"`
+}
+
+// FromBlock reports whether the subnet is carved from an IP block.
+func (s *IPPoolSubnet) FromBlock() bool { return s.ResourceType == "IpAddressPoolBlockSubnet" }
+
+// IPBlockList represents one page of GET ip-blocks.
+type IPBlockList struct {
+	ResultCount int       `json:"result_count"`
+	Cursor      string    `json:"cursor"`
+	Results     []IPBlock `json:"results"`
+}
+
+// IPBlock is one Policy IP address block.
+type IPBlock struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Path        string `json:"path"`
+	CIDR        string `json:"cidr"`
+}
+
+// Size returns how many addresses the block's CIDR spans (saturating at
+// MaxInt64 for large IPv6 blocks), 0 when the CIDR does not parse.
+func (b *IPBlock) Size() int64 { return CIDRSize(b.CIDR) }
+
+// CIDRSize returns the number of addresses in cidr, saturating at MaxInt64;
+// 0 when cidr does not parse.
+func CIDRSize(cidr string) int64 {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0
+	}
+	ones, bits := n.Mask.Size()
+	return saturateInt64(new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)))
+}
+
+// GetIPPools lists every Policy IP pool with its pool_usage.
+func (c *Client) GetIPPools(ctx context.Context) ([]IPPool, error) {
+	var all []IPPool
+	cursor := ""
+	for {
+		path := "/policy/api/v1/infra/ip-pools?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page IPPoolList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("ip pools: %w", err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
+// GetIPPoolSubnets lists the subnets of one IP pool.
+func (c *Client) GetIPPoolSubnets(ctx context.Context, poolID string) ([]IPPoolSubnet, error) {
+	var all []IPPoolSubnet
+	cursor := ""
+	for {
+		path := "/policy/api/v1/infra/ip-pools/" + poolID + "/ip-subnets?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page IPPoolSubnetList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("ip pool %s subnets: %w", poolID, err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
+// GetIPBlocks lists every Policy IP block.
+func (c *Client) GetIPBlocks(ctx context.Context) ([]IPBlock, error) {
+	var all []IPBlock
+	cursor := ""
+	for {
+		path := "/policy/api/v1/infra/ip-blocks?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page IPBlockList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("ip blocks: %w", err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
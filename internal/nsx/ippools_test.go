@@ -0,0 +1,70 @@
+package nsx
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetIPPoolsUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy/api/v1/infra/ip-pools" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"results":[
+			{"id":"tep","display_name":"TEP-POOL","pool_usage":{"total_ips":254,"allocated_ip_allocations":200,"available_ips":54,"requested_ip_allocations":200}},
+			{"id":"v6","display_name":"V6-POOL","pool_usage":{"total_ips":"18446744073709551616","allocated_ip_allocations":"3","available_ips":"18446744073709551613"}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	pools, err := c.GetIPPools(context.Background())
+	if err != nil {
+		t.Fatalf("GetIPPools: %v", err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("pools = %d, want 2", len(pools))
+	}
+	if u := pools[0].PoolUsage; u.TotalIPs != 254 || u.AllocatedIPAllocations != 200 || u.AvailableIPs != 54 {
+		t.Errorf("TEP-POOL usage = %+v", u)
+	}
+	if u := pools[1].PoolUsage; u.TotalIPs != math.MaxInt64 || u.AllocatedIPAllocations != 3 {
+		t.Errorf("V6-POOL usage = %+v, want saturated total", u)
+	}
+}
+
+func TestCIDRSize(t *testing.T) {
+	for cidr, want := range map[string]int64{
+		"10.10.0.0/16":   65536,
+		"192.168.1.0/24": 256,
+		"10.0.0.1/32":    1,
+		"2001:db8::/64":  math.MaxInt64,
+		"2001:db8::/72":  1 << 56,
+		"not-a-cidr":     0,
+	} {
+		if got := CIDRSize(cidr); got != want {
+			t.Errorf("CIDRSize(%q) = %d, want %d", cidr, got, want)
+		}
+	}
+}
+
+func TestIPCountAdd(t *testing.T) {
+	cases := []struct{ n, m, want IPCount }{
+		{64, 64, 128},
+		{math.MaxInt64 - 1, 1, math.MaxInt64},
+		{1 << 62, 1 << 62, math.MaxInt64},
+		{math.MaxInt64, math.MaxInt64, math.MaxInt64},
+		{math.MinInt64 + 1, -2, math.MinInt64},
+	}
+	for _, tc := range cases {
+		if got := tc.n.Add(tc.m); got != tc.want {
+			t.Errorf("%d.Add(%d) = %d, want %d", tc.n, tc.m, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package nsx
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LimiterConfig sets the request budget for one Manager. Zero values fall
+// back to the defaults in NewLimiter.
+type LimiterConfig struct {
+	// RequestsPerSec is the steady-state budget the limiter recovers to.
+	RequestsPerSec float64
+	// MinRequestsPerSec is the floor the budget never backs off below.
+	MinRequestsPerSec float64
+	// MaxInFlight caps concurrent requests to the Manager.
+	MaxInFlight int
+	// LatencyThreshold: responses slower than this shrink the budget, since
+	// a Manager that is about to start answering 429 slows down first.
+	LatencyThreshold time.Duration
+}
+
+// Limiter is an adaptive token bucket shared by every caller of one Client
+// (Worker, HACollector, CapacityCollector). The budget is cut multiplicatively
+// on 429 or latency spikes and grows back additively while the Manager is
+// healthy, so a throttled site converges instead of oscillating.
+type Limiter struct {
+	mu       sync.Mutex
+	cfg      LimiterConfig
+	rate     float64 // current tokens/sec
+	tokens   float64 // may go negative: outstanding reservations
+	last     time.Time
+	lastCut  time.Time
+	lastGrow time.Time
+	inflight chan struct{}
+}
+
+const (
+	limiterCutCooldown  = 1 * time.Second // one cut per burst of 429s
+	limiterGrowInterval = 2 * time.Second // pace of additive recovery
+	limiterGrowSteps    = 20              // floor → max in ~20 steps (~40s)
+)
+
+// NewLimiter builds a limiter starting at the full budget.
+func NewLimiter(cfg LimiterConfig) *Limiter {
+	if cfg.RequestsPerSec <= 0 {
+		cfg.RequestsPerSec = 25
+	}
+	if cfg.MinRequestsPerSec <= 0 || cfg.MinRequestsPerSec > cfg.RequestsPerSec {
+		cfg.MinRequestsPerSec = math.Min(2, cfg.RequestsPerSec)
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 8
+	}
+	if cfg.LatencyThreshold <= 0 {
+		cfg.LatencyThreshold = 3 * time.Second
+	}
+	now := time.Now()
+	return &Limiter{
+		cfg:      cfg,
+		rate:     cfg.RequestsPerSec,
+		tokens:   1,
+		last:     now,
+		lastGrow: now,
+		inflight: make(chan struct{}, cfg.MaxInFlight),
+	}
+}
+
+// Acquire blocks until the caller may send one request: a token is taken
+// from the bucket and an in-flight slot is held until release is called.
+// wait is the total time spent queued.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), wait time.Duration, err error) {
+	start := time.Now()
+
+	l.mu.Lock()
+	l.refill(start)
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.mu.Lock()
+			l.tokens++ // give the reservation back
+			l.mu.Unlock()
+			return nil, time.Since(start), ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	select {
+	case l.inflight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, time.Since(start), ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l.inflight }) }, time.Since(start), nil
+}
+
+// Observe feeds the outcome of one request back into the budget.
+func (l *Limiter) Observe(status int, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		l.cut(now, 0.5)
+	case latency > l.cfg.LatencyThreshold:
+		l.cut(now, 0.8)
+	case status > 0 && status < 500:
+		if l.rate < l.cfg.RequestsPerSec && now.Sub(l.lastGrow) >= limiterGrowInterval && now.Sub(l.lastCut) >= limiterGrowInterval {
+			l.rate = math.Min(l.cfg.RequestsPerSec, l.rate+l.cfg.RequestsPerSec/limiterGrowSteps)
+			l.lastGrow = now
+		}
+	}
+}
+
+// Rate returns the current budget in requests/sec.
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// InFlight returns the number of requests currently holding a slot.
+func (l *Limiter) InFlight() int { return len(l.inflight) }
+
+// MaxInFlight returns the configured concurrency cap. Fan-out callers use it
+// to size their worker pools so goroutines don't pile up on the limiter.
+func (l *Limiter) MaxInFlight() int { return cap(l.inflight) }
+
+// refill adds tokens for the time elapsed since the last call. Caller holds mu.
+func (l *Limiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens = math.Min(1, l.tokens+elapsed*l.rate)
+}
+
+// cut shrinks the budget by factor, at most once per limiterCutCooldown so a
+// burst of 429s from the same window counts as a single signal. Caller holds mu.
+func (l *Limiter) cut(now time.Time, factor float64) {
+	if now.Sub(l.lastCut) < limiterCutCooldown {
+		return
+	}
+	l.refill(now)
+	l.rate = math.Max(l.cfg.MinRequestsPerSec, l.rate*factor)
+	l.lastCut = now
+	l.lastGrow = now
+}
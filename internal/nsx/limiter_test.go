@@ -0,0 +1,89 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"nsx-collector/internal/telemetry"
+)
+
+func TestLimiterBacksOffAndRespectsFloor(t *testing.T) {
+	l := NewLimiter(LimiterConfig{RequestsPerSec: 20, MinRequestsPerSec: 4, MaxInFlight: 2})
+
+	l.Observe(http.StatusTooManyRequests, 10*time.Millisecond)
+	if got := l.Rate(); got != 10 {
+		t.Fatalf("rate after 429 = %v, want 10", got)
+	}
+
+	// A second 429 inside the cooldown window is the same burst: no extra cut.
+	l.Observe(http.StatusTooManyRequests, 10*time.Millisecond)
+	if got := l.Rate(); got != 10 {
+		t.Fatalf("rate after burst 429 = %v, want 10", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		l.lastCut = time.Time{}
+		l.Observe(http.StatusTooManyRequests, 10*time.Millisecond)
+	}
+	if got := l.Rate(); got != 4 {
+		t.Fatalf("rate after repeated 429 = %v, want floor 4", got)
+	}
+
+	// Healthy responses grow the budget back once the grow interval elapsed.
+	l.lastCut = time.Time{}
+	l.lastGrow = time.Time{}
+	l.Observe(http.StatusOK, 10*time.Millisecond)
+	if got := l.Rate(); got != 5 {
+		t.Fatalf("rate after recovery step = %v, want 5", got)
+	}
+}
+
+func TestLimiterCapsInFlight(t *testing.T) {
+	l := NewLimiter(LimiterConfig{RequestsPerSec: 1000, MaxInFlight: 1})
+	ctx := context.Background()
+
+	release, _, err := l.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	blocked, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := l.Acquire(blocked); err == nil {
+		t.Fatalf("second Acquire succeeded while slot was held")
+	}
+
+	release()
+	if _, _, err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+}
+
+func TestClientInFlightGaugeDropsOnRelease(t *testing.T) {
+	c, err := NewClient(Options{BaseURL: "http://127.0.0.1:1", Site: "GAUGE"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	gauge := func() float64 {
+		var m dto.Metric
+		if err := telemetry.NSXRequestsInFlight.WithLabelValues("GAUGE").Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	release, err := c.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if got := gauge(); got != 1 {
+		t.Fatalf("in flight while held = %v, want 1", got)
+	}
+	release()
+	if got := gauge(); got != 0 {
+		t.Fatalf("in flight after release = %v, want 0", got)
+	}
+}
@@ -1,6 +1,9 @@
 package nsx
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+)
 
 // NodeStatus represents GET /api/v1/cluster/nodes/<id>/status — appliance
 // status of one Manager (or Controller) cluster node, returned as a
@@ -8,8 +11,55 @@ import "encoding/json"
 // /api/v1/node/status which returns the same fields at the root.
 type NodeStatus struct {
 	SystemStatus struct {
-		Uptime int64 `json:"uptime"` // milliseconds since last reboot
+		Uptime      int64     `json:"uptime"` // milliseconds since last reboot
+		CPUCores    int       `json:"cpu_cores"`
+		LoadAverage []float64 `json:"load_average"`
+		// Memory and swap are in kB.
+		MemTotal  int64 `json:"mem_total"`
+		MemUsed   int64 `json:"mem_used"`
+		MemCache  int64 `json:"mem_cache"`
+		SwapTotal int64 `json:"swap_total"`
+		SwapUsed  int64 `json:"swap_used"`
+		// CPUUsage is only reported by NSX 3.0+; Managers have no DPDK cores,
+		// so the non_dpdk pair is the appliance CPU.
+		CPUUsage struct {
+			HighestCPUCoreNonDPDK float64 `json:"highest_cpu_core_usage_non_dpdk"`
+			AvgCPUCoreNonDPDK     float64 `json:"avg_cpu_core_usage_non_dpdk"`
+		} `json:"cpu_usage"`
+		FileSystems []NodeFileSystem `json:"file_systems"`
 	} `json:"system_status"`
+	// Version is the NSX build the node runs (e.g. 3.2.3.1.0.22104592).
+	Version string `json:"version"`
+}
+
+// NodeFileSystem is one mounted filesystem of an appliance (sizes in kB).
+type NodeFileSystem struct {
+	FileSystem string `json:"file_system"`
+	Mount      string `json:"mount"`
+	Type       string `json:"type"`
+	Total      int64  `json:"total"`
+	Used       int64  `json:"used"`
+}
+
+// NodeServiceStatus represents GET .../node/services/<name>/status.
+type NodeServiceStatus struct {
+	RuntimeState        string `json:"runtime_state"` // running | stopped
+	MonitorRuntimeState string `json:"monitor_runtime_state"`
+}
+
+// Running reports whether the service process is up.
+func (s *NodeServiceStatus) Running() bool {
+	return strings.EqualFold(s.RuntimeState, "running")
+}
+
+// ManagerServices are the Manager appliance services whose state is
+// collected, as (label, NSX service name): proton is the "manager" service
+// and corfu the "datastore" one.
+var ManagerServices = []struct{ Label, Name string }{
+	{"proton", "manager"},
+	{"corfu", "datastore"},
+	{"search", "search"},
+	{"http", "http"},
 }
 
 // ClusterStatus represents GET /api/v1/cluster/status
@@ -24,10 +74,39 @@ type ClusterStatus struct {
 		Status string `json:"status"`
 	} `json:"control_cluster_status"`
 	DetailedClusterStatus struct {
-		OverallStatus string `json:"overall_status"`
+		OverallStatus string         `json:"overall_status"`
+		Groups        []ClusterGroup `json:"groups"`
 	} `json:"detailed_cluster_status"`
 }
 
+// ClusterGroup is one service group of the cluster (MANAGER, CONTROLLER,
+// POLICY, HTTPS, DATASTORE, CLUSTER_BOOT_MANAGER, ...).
+type ClusterGroup struct {
+	GroupID     string               `json:"group_id"`
+	GroupType   string               `json:"group_type"`
+	GroupStatus string               `json:"group_status"` // STABLE | DEGRADED | UNAVAILABLE
+	Members     []ClusterGroupMember `json:"members"`
+}
+
+// ClusterGroupMember is one node's participation in a cluster group.
+type ClusterGroupMember struct {
+	MemberUUID   string `json:"member_uuid"`
+	MemberIP     string `json:"member_ip"`
+	MemberFQDN   string `json:"member_fqdn"`
+	MemberStatus string `json:"member_status"` // UP | DOWN | UNKNOWN
+}
+
+// MembersUp counts the members reporting UP.
+func (g *ClusterGroup) MembersUp() int {
+	n := 0
+	for _, m := range g.Members {
+		if strings.EqualFold(m.MemberStatus, "UP") {
+			n++
+		}
+	}
+	return n
+}
+
 // ClusterNode is a node entry in cluster status.
 type ClusterNode struct {
 	UUID                       string `json:"uuid"`
@@ -186,6 +265,29 @@ func (p *LogicalRouterPort) LinkedPortID() string {
 	return ""
 }
 
+// LogicalRouterPortStatistics represents
+// GET /api/v1/logical-router-ports/{id}/statistics — cumulative counters per
+// transport node carrying the port (edge nodes for a T1 with an SR).
+type LogicalRouterPortStatistics struct {
+	LogicalRouterPortID string                   `json:"logical_router_port_id"`
+	PerNodeStatistics   []LogicalRouterPortStats `json:"per_node_statistics"`
+}
+
+// LogicalRouterPortStats is one transport node's share of the port counters.
+type LogicalRouterPortStats struct {
+	TransportNodeID     string       `json:"transport_node_id"`
+	LastUpdateTimestamp int64        `json:"last_update_timestamp"`
+	Rx                  PortCounters `json:"rx"`
+	Tx                  PortCounters `json:"tx"`
+}
+
+// PortCounters are cumulative since the datapath (re)started.
+type PortCounters struct {
+	TotalBytes     int64 `json:"total_bytes"`
+	TotalPackets   int64 `json:"total_packets"`
+	DroppedPackets int64 `json:"dropped_packets"`
+}
+
 // NetworkInterfaceList represents GET /api/v1/transport-nodes/{id}/network/interfaces
 type NetworkInterfaceList struct {
 	ResultCount int                `json:"result_count"`
@@ -412,6 +514,72 @@ type PolicyTier0 struct {
 // IsVRF returns true when the T0 is a VRF gateway (has vrf_config set).
 func (t *PolicyTier0) IsVRF() bool { return len(t.VRFConfig) > 0 }
 
+// VRFParentPath returns the path of the T0 a VRF is built on
+// (vrf_config.tier0_path), or "" for a regular T0.
+func (t *PolicyTier0) VRFParentPath() string {
+	if !t.IsVRF() {
+		return ""
+	}
+	var cfg struct {
+		Tier0Path string `json:"tier0_path"`
+	}
+	if err := json.Unmarshal(t.VRFConfig, &cfg); err != nil {
+		return ""
+	}
+	return cfg.Tier0Path
+}
+
+// PolicyLocaleServicesList represents GET /policy/api/v1/infra/tier-0s/{id}/locale-services
+type PolicyLocaleServicesList struct {
+	ResultCount int                    `json:"result_count"`
+	Cursor      string                 `json:"cursor"`
+	Results     []PolicyLocaleServices `json:"results"`
+}
+
+// PolicyLocaleServices is one locale-services entry of a T0/VRF (usually
+// "default"); BGP config and status hang off its path.
+type PolicyLocaleServices struct {
+	ID              string `json:"id"`
+	DisplayName     string `json:"display_name"`
+	Path            string `json:"path"` // /infra/tier-0s/<id>/locale-services/<ls>
+	EdgeClusterPath string `json:"edge_cluster_path,omitempty"`
+}
+
+// ---------------------------------------------------------------------------
+// Policy API — BGP neighbor status
+// GET /policy/api/v1/infra/tier-0s/{id}/locale-services/{ls}/bgp/neighbors/status
+// One entry per (neighbor, edge node): each edge of the T0 runs its own session.
+// ---------------------------------------------------------------------------
+
+// BGPNeighborStatusList represents GET .../bgp/neighbors/status.
+type BGPNeighborStatusList struct {
+	LastUpdateTimestamp int64               `json:"last_update_timestamp"`
+	Results             []BGPNeighborStatus `json:"results"`
+}
+
+// BGPNeighborStatus is the state of one BGP session on one edge node.
+type BGPNeighborStatus struct {
+	NeighborAddress string `json:"neighbor_address"`
+	SourceAddress   string `json:"source_address"`
+	RemoteASNumber  string `json:"remote_as_number"`
+	// ConnectionState: ESTABLISHED, IDLE, CONNECT, ACTIVE, OPEN_SENT, OPEN_CONFIRM.
+	ConnectionState            string `json:"connection_state"`
+	TimeSinceEstablished       int64  `json:"time_since_established"` // ms, 0 when down
+	TotalInPrefixCount         int64  `json:"total_in_prefix_count"`
+	TotalOutPrefixCount        int64  `json:"total_out_prefix_count"`
+	EstablishedConnectionCount int64  `json:"established_connection_count"`
+	ConnectionDropCount        int64  `json:"connection_drop_count"` // cumulative flaps
+	TransportNode              struct {
+		TargetID          string `json:"target_id"`
+		TargetDisplayName string `json:"target_display_name"`
+	} `json:"transport_node"`
+}
+
+// Established reports whether the session is up.
+func (n *BGPNeighborStatus) Established() bool {
+	return strings.EqualFold(strings.TrimSpace(n.ConnectionState), "ESTABLISHED")
+}
+
 // PolicyTier1List represents GET /policy/api/v1/infra/tier-1s
 type PolicyTier1List struct {
 	ResultCount int           `json:"result_count"`
@@ -429,6 +597,10 @@ type PolicyTier1 struct {
 	UniqueID    string `json:"unique_id"`    // matches legacy logical-router UUID
 	CreateTime  int64  `json:"_create_time"` // epoch ms — used to detect freshness
 	CreateUser  string `json:"_create_user"`
+	// Revision / LastModifiedTime change on every edit of the T1 itself
+	// (not of its child NAT rules) — one of the incremental-capacity signals.
+	Revision         int64 `json:"_revision"`
+	LastModifiedTime int64 `json:"_last_modified_time"` // epoch ms
 }
 
 // PolicySegmentList represents GET /policy/api/v1/infra/segments
@@ -459,6 +631,66 @@ type PolicyNATRuleList struct {
 	ResultCount int `json:"result_count"`
 }
 
+// ---------------------------------------------------------------------------
+// Policy API — static routes per gateway
+// GET /policy/api/v1/infra/tier-{0,1}s/{id}/static-routes?page_size=1
+// ---------------------------------------------------------------------------
+
+// PolicyStaticRouteList represents GET static-routes — we only need the count.
+type PolicyStaticRouteList struct {
+	ResultCount int `json:"result_count"`
+}
+
+// ---------------------------------------------------------------------------
+// Policy API — routing / forwarding table per gateway and edge node
+// GET /policy/api/v1/infra/tier-0s/{id}/routing-table
+// GET /policy/api/v1/infra/tier-0s/{id}/forwarding-table
+// One result per edge node hosting the gateway's service router.
+// ---------------------------------------------------------------------------
+
+// Route tables (the path segment after the gateway).
+const (
+	RoutingTable    = "routing-table"
+	ForwardingTable = "forwarding-table"
+)
+
+// RouteTableList represents GET routing-table / forwarding-table.
+type RouteTableList struct {
+	ResultCount int              `json:"result_count"`
+	Cursor      string           `json:"cursor"`
+	Results     []RouteTableEdge `json:"results"`
+}
+
+// RouteTableEdge is the table of one edge node.
+type RouteTableEdge struct {
+	EdgeNode     string       `json:"edge_node"` // .../edge-clusters/<ec>/edge-nodes/<tn-id>
+	Count        int64        `json:"count"`
+	RouteEntries []RouteEntry `json:"route_entries"`
+}
+
+// RouteEntry is one route; only the type is used (counts per category).
+type RouteEntry struct {
+	RouteType string `json:"route_type"` // t0c, t0s, b, t0n, t1c, ...
+	Network   string `json:"network"`
+}
+
+// RouteCategory maps an NSX route_type code to connected, static, bgp, nat
+// or other. Codes: t0c/t1c connected, t0s/t1s static, b BGP, t0n/t1n NAT;
+// anything else (ospf, LB VIP, IPSec, inter-SR) counts as other.
+func RouteCategory(routeType string) string {
+	switch strings.ToLower(strings.TrimSpace(routeType)) {
+	case "t0c", "t1c", "c", "connected":
+		return "connected"
+	case "t0s", "t1s", "s", "static":
+		return "static"
+	case "b", "bgp", "b_ebgp", "b_ibgp":
+		return "bgp"
+	case "t0n", "t1n", "nat":
+		return "nat"
+	}
+	return "other"
+}
+
 // ---------------------------------------------------------------------------
 // Policy API — Gateway Firewall policies per Tier-1
 // GET /policy/api/v1/infra/domains/default/gateway-policies?include_rule_count=true
@@ -505,8 +737,8 @@ type PolicyEdgeCluster struct {
 // Policy API — Groups with effective member counts (for waste/orphan analysis)
 // GET /policy/api/v1/infra/domains/default/groups
 // Effective members fetched per-group via /members/virtual-machines etc.
-// To keep cost low we just expose the list endpoint here; the worker will
-// sample effective counts on demand (top-N or rotating sample).
+// (groups.go). To keep cost low the capacity collector samples effective
+// counts for a rotating slice of groups (capacity.group_members_sample).
 // ---------------------------------------------------------------------------
 
 // PolicyGroupList represents GET groups.
@@ -0,0 +1,69 @@
+package nsx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRouteCategory(t *testing.T) {
+	cases := map[string]string{
+		"t0c": "connected",
+		"T1C": "connected",
+		"t0s": "static",
+		"b":   "bgp",
+		"t0n": "nat",
+		"t1n": "nat",
+		"t1l": "other",
+		"isr": "other",
+		"":    "other",
+	}
+	for in, want := range cases {
+		if got := RouteCategory(in); got != want {
+			t.Errorf("RouteCategory(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestVRFParentPath(t *testing.T) {
+	vrf := PolicyTier0{VRFConfig: []byte(`{"tier0_path":"/infra/tier-0s/T0-A"}`)}
+	if got := vrf.VRFParentPath(); got != "/infra/tier-0s/T0-A" {
+		t.Fatalf("VRFParentPath = %q", got)
+	}
+	var t0 PolicyTier0
+	if got := t0.VRFParentPath(); got != "" {
+		t.Fatalf("regular T0 VRFParentPath = %q, want empty", got)
+	}
+}
+
+func TestNodeStatusDecode(t *testing.T) {
+	body := `{"system_status": {
+		"uptime": 86400000, "cpu_cores": 12, "load_average": [1.5, 1.2, 0.9],
+		"mem_total": 49152000, "mem_used": 36864000, "mem_cache": 4096000,
+		"swap_total": 0, "swap_used": 0,
+		"cpu_usage": {"avg_cpu_core_usage_non_dpdk": 37.5, "highest_cpu_core_usage_non_dpdk": 88.0},
+		"file_systems": [
+			{"file_system": "/dev/sda2", "mount": "/", "type": "ext4", "total": 10000, "used": 4000},
+			{"file_system": "/dev/mapper/nsx-config", "mount": "/config", "type": "ext4", "total": 20000, "used": 19000}
+		]
+	}}`
+	var ns NodeStatus
+	if err := json.Unmarshal([]byte(body), &ns); err != nil {
+		t.Fatal(err)
+	}
+	sys := ns.SystemStatus
+	if sys.Uptime != 86400000 || sys.CPUCores != 12 || len(sys.LoadAverage) != 3 {
+		t.Errorf("system_status = %+v", sys)
+	}
+	if sys.CPUUsage.AvgCPUCoreNonDPDK != 37.5 || sys.MemUsed != 36864000 {
+		t.Errorf("cpu/mem = %+v", sys)
+	}
+	if len(sys.FileSystems) != 2 || sys.FileSystems[1].Mount != "/config" || sys.FileSystems[1].Used != 19000 {
+		t.Errorf("file_systems = %+v", sys.FileSystems)
+	}
+
+	for state, want := range map[string]bool{"running": true, "RUNNING": true, "stopped": false, "": false} {
+		if got := (&NodeServiceStatus{RuntimeState: state}).Running(); got != want {
+			t.Errorf("Running(%q) = %v, want %v", state, got, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Normalized realization states, shared by transport nodes and Policy
+// objects.
+const (
+	RealizationSuccess    = "SUCCESS"
+	RealizationInProgress = "IN_PROGRESS"
+	RealizationError      = "ERROR"
+	RealizationUnknown    = "UNKNOWN"
+)
+
+// ---------------------------------------------------------------------------
+// Transport node configuration state
+// GET /api/v1/transport-nodes/state (list form of /transport-nodes/<id>/state)
+// ---------------------------------------------------------------------------
+
+// TransportNodeStateList represents one page of transport node states.
+type TransportNodeStateList struct {
+	ResultCount int                  `json:"result_count"`
+	Cursor      string               `json:"cursor"`
+	Results     []TransportNodeState `json:"results"`
+}
+
+// TransportNodeState is the configuration (realization) state of one
+// transport node, as shown on the NSX UI "Configuration State" column.
+type TransportNodeState struct {
+	TransportNodeID string `json:"transport_node_id"`
+	// State: success | in_progress | pending | failed | partial_success |
+	// orphaned | error | ...
+	State               string `json:"state"`
+	FailureCode         int    `json:"failure_code,omitempty"`
+	FailureMessage      string `json:"failure_message,omitempty"`
+	NodeDeploymentState struct {
+		State          string `json:"state"`
+		FailureMessage string `json:"failure_message,omitempty"`
+	} `json:"node_deployment_state"`
+	Details []struct {
+		SubSystemType  string `json:"sub_system_type"`
+		State          string `json:"state"`
+		FailureMessage string `json:"failure_message,omitempty"`
+	} `json:"details,omitempty"`
+}
+
+// Status returns the normalized state (RealizationSuccess, ...).
+func (s *TransportNodeState) Status() string {
+	switch strings.ToLower(s.State) {
+	case "success":
+		return RealizationSuccess
+	case "in_progress", "pending":
+		return RealizationInProgress
+	case "failed", "partial_success", "orphaned", "error":
+		return RealizationError
+	}
+	return RealizationUnknown
+}
+
+// Message returns the first failure message reported for the node, its
+// deployment or one of its sub-systems; "" when there is none.
+func (s *TransportNodeState) Message() string {
+	if s.FailureMessage != "" {
+		return s.FailureMessage
+	}
+	if s.NodeDeploymentState.FailureMessage != "" {
+		return s.NodeDeploymentState.FailureMessage
+	}
+	for _, d := range s.Details {
+		if d.FailureMessage != "" {
+			return d.SubSystemType + ": " + d.FailureMessage
+		}
+	}
+	return ""
+}
+
+// GetTransportNodeStates returns the configuration state of every transport
+// node in a few paginated calls.
+func (c *Client) GetTransportNodeStates(ctx context.Context) ([]TransportNodeState, error) {
+	var all []TransportNodeState
+	cursor := ""
+	for {
+		path := "/api/v1/transport-nodes/state?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page TransportNodeStateList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("transport node states: %w", err)
+		}
+		all = append(all, page.Results...)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
+
+// ---------------------------------------------------------------------------
+// Policy realized state
+// GET /policy/api/v1/infra/realized-state/status?intent_path=<path>
+// ---------------------------------------------------------------------------
+
+// RealizedStatus is the consolidated realization status of one Policy
+// intent object across enforcement points.
+type RealizedStatus struct {
+	IntentPath string `json:"intent_path"`
+	// PublishStatus: REALIZED | UNREALIZED | UNINITIALIZED | IN_PROGRESS | ERROR
+	PublishStatus      string `json:"publish_status"`
+	ConsolidatedStatus struct {
+		// SUCCESS | IN_PROGRESS | ERROR | UNKNOWN
+		ConsolidatedStatus string `json:"consolidated_status"`
+	} `json:"consolidated_status"`
+}
+
+// Status returns the normalized state, from the consolidated status or,
+// when absent (older releases), from publish_status.
+func (r *RealizedStatus) Status() string {
+	switch strings.ToUpper(r.ConsolidatedStatus.ConsolidatedStatus) {
+	case "SUCCESS":
+		return RealizationSuccess
+	case "IN_PROGRESS":
+		return RealizationInProgress
+	case "ERROR":
+		return RealizationError
+	}
+	switch strings.ToUpper(r.PublishStatus) {
+	case "REALIZED":
+		return RealizationSuccess
+	case "IN_PROGRESS", "UNREALIZED":
+		return RealizationInProgress
+	case "ERROR", "FAILED":
+		return RealizationError
+	}
+	return RealizationUnknown
+}
+
+// GetRealizedStatus returns the realization status of the Policy object at
+// intentPath (e.g. /infra/tier-1s/<id>).
+func (c *Client) GetRealizedStatus(ctx context.Context, intentPath string) (*RealizedStatus, error) {
+	var result RealizedStatus
+	path := "/policy/api/v1/infra/realized-state/status?intent_path=" + url.QueryEscape(intentPath)
+	if err := c.doGet(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("realized status %s: %w", intentPath, err)
+	}
+	return &result, nil
+}
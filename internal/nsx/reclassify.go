@@ -44,13 +44,7 @@ func (a *Alarm) OperationalSeverity() string {
 	// Certificate Expired: self-signed internal certs (Corfu/APH_TN/CCP) are noise
 	// and drop to WARNING; CA-signed certs (API/UI/inter-site) stay CRITICAL.
 	if key == "certificate expired" {
-		summary := strings.ToLower(a.Summary)
-		if strings.Contains(summary, "self-signed") ||
-			strings.Contains(summary, "self signed") ||
-			strings.Contains(summary, "self_signed") {
-			return "WARNING"
-		}
-		return a.Severity
+		return CertificateExpirySeverity(IsSelfSignedText(a.Summary), a.Severity)
 	}
 
 	if sev, ok := operationalSeverityByEventType[key]; ok {
@@ -58,3 +52,23 @@ func (a *Alarm) OperationalSeverity() string {
 	}
 	return a.Severity
 }
+
+// IsSelfSignedText reports whether s marks a certificate as self-signed, in any
+// of the spellings NSX uses: "self-signed" in alarm summaries,
+// "certificate_self_signed" as a trust-management resource_type.
+func IsSelfSignedText(s string) bool {
+	s = strings.ToLower(s)
+	return strings.Contains(s, "self-signed") ||
+		strings.Contains(s, "self signed") ||
+		strings.Contains(s, "self_signed")
+}
+
+// CertificateExpirySeverity is the operational severity of an expired (or
+// expiring) certificate: self-signed internal certs drop to WARNING, CA-signed
+// certs keep caSigned (the vendor severity for alarms).
+func CertificateExpirySeverity(selfSigned bool, caSigned string) string {
+	if selfSigned {
+		return "WARNING"
+	}
+	return caSigned
+}
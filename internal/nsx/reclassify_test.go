@@ -39,3 +39,19 @@ func TestOperationalSeverity(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSelfSignedText(t *testing.T) {
+	cases := map[string]bool{
+		"A self-signed certificate has expired": true,
+		"Self Signed cert":                      true,
+		"certificate_self_signed":               true,
+		"certificate_signed":                    false,
+		"certificate_ca":                        false,
+		"":                                      false,
+	}
+	for in, want := range cases {
+		if got := IsSelfSignedText(in); got != want {
+			t.Errorf("IsSelfSignedText(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
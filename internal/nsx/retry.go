@@ -0,0 +1,187 @@
+package nsx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Endpoint classes used to pick a RetryPolicy override.
+const (
+	// EndpointStatus is a cheap per-object read (.../status, .../state,
+	// .../stats). Failing fast is better than holding up the cycle.
+	EndpointStatus = "status"
+	// EndpointInventory is an expensive list (transport nodes, T1s,
+	// segments, groups). Worth a few more attempts: losing it drops a whole
+	// section of the cycle.
+	EndpointInventory = "inventory"
+)
+
+// RetryPolicy controls how doGet retries transient failures: HTTP statuses in
+// RetryStatuses plus transport errors (connection reset/refused, unexpected
+// EOF, timeouts — what the Manager VIP does while a node reboots). Zero
+// values fall back to the defaults in withDefaults.
+type RetryPolicy struct {
+	// MaxAttempts counts the first try. 1 disables retries.
+	MaxAttempts int
+	// BaseBackoff is the first wait; it doubles per attempt up to MaxBackoff.
+	// The actual wait is jittered in [backoff/2, backoff).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Deadline bounds the whole call, attempts and waits included.
+	Deadline time.Duration
+	// RetryStatuses are the HTTP statuses worth retrying.
+	RetryStatuses []int
+}
+
+// withDefaults fills zero fields with the collector defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 4
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = 500 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.MaxBackoff < p.BaseBackoff {
+		p.MaxBackoff = p.BaseBackoff
+	}
+	if p.Deadline <= 0 {
+		p.Deadline = 60 * time.Second
+	}
+	if len(p.RetryStatuses) == 0 {
+		p.RetryStatuses = []int{429, 502, 503, 504}
+	}
+	return p
+}
+
+// merge returns p with every non-zero field of o applied on top.
+func (p RetryPolicy) merge(o RetryPolicy) RetryPolicy {
+	if o.MaxAttempts > 0 {
+		p.MaxAttempts = o.MaxAttempts
+	}
+	if o.BaseBackoff > 0 {
+		p.BaseBackoff = o.BaseBackoff
+	}
+	if o.MaxBackoff > 0 {
+		p.MaxBackoff = o.MaxBackoff
+	}
+	if o.Deadline > 0 {
+		p.Deadline = o.Deadline
+	}
+	if len(o.RetryStatuses) > 0 {
+		p.RetryStatuses = o.RetryStatuses
+	}
+	return p
+}
+
+// buildRetryPolicies resolves the effective policy for each endpoint class.
+func buildRetryPolicies(base RetryPolicy, overrides map[string]RetryPolicy) map[string]RetryPolicy {
+	out := map[string]RetryPolicy{}
+	for _, class := range []string{EndpointStatus, EndpointInventory} {
+		out[class] = base.merge(overrides[class]).withDefaults()
+	}
+	return out
+}
+
+// retryDelay reports whether err is worth another attempt under p and, for a
+// 429 carrying Retry-After, the wait the Manager asked for.
+func (p RetryPolicy) retryDelay(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, s := range p.RetryStatuses {
+			if apiErr.StatusCode == s {
+				return apiErr.retryAfter, true
+			}
+		}
+		return 0, false
+	}
+	return 0, isTransientTransport(err)
+}
+
+// isTransientTransport reports whether err is a network failure that a retry
+// may cure. Certificate errors and caller cancellation are not.
+func isTransientTransport(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	// Covers http.Client.Timeout and "net/http: TLS handshake timeout".
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// jitter returns a wait in [d/2, d) so concurrent callers that failed
+// together don't retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// endpointClass classifies a request path for RetryPolicy overrides.
+func endpointClass(path string) string {
+	p := path
+	if i := strings.IndexByte(p, '?'); i >= 0 {
+		p = p[:i]
+	}
+	switch p[strings.LastIndexByte(p, '/')+1:] {
+	case "status", "state", "stats", "statistics", "version":
+		return EndpointStatus
+	}
+	return EndpointInventory
+}
+
+// idCollections are path segments whose next segment is an object ID.
+var idCollections = map[string]bool{
+	"nodes":                true,
+	"transport-nodes":      true,
+	"logical-routers":      true,
+	"logical-router-ports": true,
+	"interfaces":           true,
+	"services":             true,
+	"tier-0s":              true,
+	"tier-1s":              true,
+	"locale-services":      true,
+	"gateway-policies":     true,
+	"groups":               true,
+	"ip-pools":             true,
+}
+
+// collectionActions follow an idCollections segment but name a
+// collection-wide endpoint, not an object ("/transport-nodes/state").
+var collectionActions = map[string]bool{
+	"state": true,
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// normalizeEndpoint turns a request path into a low-cardinality telemetry
+// label: query string dropped, object IDs replaced by {id}.
+// "/api/v1/logical-routers/<uuid>/status" → "/api/v1/logical-routers/{id}/status".
+func normalizeEndpoint(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segs := strings.Split(path, "/")
+	for i := 1; i < len(segs); i++ {
+		if uuidRe.MatchString(segs[i]) || (idCollections[segs[i-1]] && segs[i] != "" && !collectionActions[segs[i]]) {
+			segs[i] = "{id}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
@@ -0,0 +1,93 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/cluster/status":
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"cluster_id":"c1"}`))
+		default:
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":202,"error_message":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{
+		BaseURL: srv.URL,
+		Retry:   RetryPolicy{BaseBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := c.GetClusterStatus(ctx); err != nil {
+		t.Fatalf("GetClusterStatus: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3 (two 503s retried)", got)
+	}
+
+	calls.Store(0)
+	_, err = c.GetPolicyTier1(ctx, "t1-gone")
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want 404 APIError", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1 (404 is not retried)", got)
+	}
+}
+
+func TestRetryOverrides(t *testing.T) {
+	// A zero base (config leaves unset fields zero) takes the nsx defaults.
+	policies := buildRetryPolicies(
+		RetryPolicy{},
+		map[string]RetryPolicy{EndpointStatus: {MaxAttempts: 2}},
+	)
+	if got := policies[EndpointStatus].MaxAttempts; got != 2 {
+		t.Fatalf("status attempts = %d, want 2", got)
+	}
+	if got := policies[EndpointInventory].MaxAttempts; got != 4 {
+		t.Fatalf("inventory attempts = %d, want 4", got)
+	}
+	if got := policies[EndpointStatus].Deadline; got != 60*time.Second {
+		t.Fatalf("status deadline = %v, want the 60s default", got)
+	}
+	if got := endpointClass("/api/v1/logical-routers/abc/status"); got != EndpointStatus {
+		t.Fatalf("class = %q, want status", got)
+	}
+	if got := endpointClass("/policy/api/v1/infra/tier-1s?page_size=200"); got != EndpointInventory {
+		t.Fatalf("class = %q, want inventory", got)
+	}
+}
+
+func TestNormalizeEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/logical-routers/3b1c2a0e-1111-2222-3333-444455556666/status":             "/api/v1/logical-routers/{id}/status",
+		"/policy/api/v1/infra/tier-1s/t1-app/nat/USER/nat-rules?page_size=1":              "/policy/api/v1/infra/tier-1s/{id}/nat/USER/nat-rules",
+		"/api/v1/transport-nodes/tn1/network/interfaces/fp-eth0/stats":                    "/api/v1/transport-nodes/{id}/network/interfaces/{id}/stats",
+		"/policy/api/v1/infra/tier-0s/vrf-a/locale-services/default/bgp/neighbors/status": "/policy/api/v1/infra/tier-0s/{id}/locale-services/{id}/bgp/neighbors/status",
+		"/api/v1/cluster/status":                       "/api/v1/cluster/status",
+		"/api/v1/transport-nodes/state?page_size=1000": "/api/v1/transport-nodes/state",
+	}
+	for in, want := range cases {
+		if got := normalizeEndpoint(in); got != want {
+			t.Errorf("normalizeEndpoint(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SearchResult is the subset of a Policy search hit the collector groups on.
+// The query asks for these fields only (included_fields), so a page of 1000
+// NAT rules stays small.
+type SearchResult struct {
+	ID           string `json:"id"`
+	DisplayName  string `json:"display_name"`
+	ResourceType string `json:"resource_type"`
+	Path         string `json:"path"`
+	ParentPath   string `json:"parent_path"`
+	// ConnectivityPath is set on segments (the T1/T0 they attach to).
+	ConnectivityPath string `json:"connectivity_path,omitempty"`
+	// Scope is set on gateway policies (the gateways they apply to). Only
+	// aggregate results carry it; SearchQuery does not ask for it.
+	Scope []string `json:"scope,omitempty"`
+}
+
+// maxSearchPages bounds a paginated search (1000 hits per page), so a
+// cursor the Manager keeps handing back cannot loop forever. A var so tests
+// can lower it.
+var maxSearchPages = 200
+
+type searchResultList struct {
+	ResultCount int            `json:"result_count"`
+	Cursor      string         `json:"cursor"`
+	Results     []SearchResult `json:"results"`
+}
+
+// SearchQuery runs GET /policy/api/v1/search/query (Lucene syntax, e.g.
+// "resource_type:PolicyNatRule") and calls fn once per page of hits.
+func (c *Client) SearchQuery(ctx context.Context, query string, fn func([]SearchResult)) error {
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages == maxSearchPages {
+			return fmt.Errorf("search %q: more than %d pages, giving up", query, maxSearchPages)
+		}
+		path := "/policy/api/v1/search/query?query=" + url.QueryEscape(query) +
+			"&page_size=1000&included_fields=id,display_name,resource_type,path,parent_path,connectivity_path"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page searchResultList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return fmt.Errorf("search %q: %w", query, err)
+		}
+		fn(page.Results)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			return nil
+		}
+		cursor = page.Cursor
+	}
+}
+
+// SearchCount returns how many objects match query, in a single call
+// (page_size=1 still populates result_count).
+func (c *Client) SearchCount(ctx context.Context, query string) (int, error) {
+	path := "/policy/api/v1/search/query?query=" + url.QueryEscape(query) + "&page_size=1&included_fields=id"
+	var page searchResultList
+	if err := c.doGet(ctx, path, &page); err != nil {
+		return 0, fmt.Errorf("search count %q: %w", query, err)
+	}
+	return page.ResultCount, nil
+}
+
+// ModifiedSince narrows query to objects created or edited at or after
+// sinceMS (epoch ms), e.g. ModifiedSince("resource_type:Segment", t).
+func ModifiedSince(query string, sinceMS int64) string {
+	return fmt.Sprintf("(%s) AND _last_modified_time:[%d TO *]", query, sinceMS)
+}
+
+// CountByParent counts the hits of query per key(hit). Hits for which key
+// returns "" are skipped. A few paginated calls replace one request per
+// parent object.
+func (c *Client) CountByParent(ctx context.Context, query string, key func(SearchResult) string) (map[string]int, error) {
+	counts := map[string]int{}
+	err := c.SearchQuery(ctx, query, func(page []SearchResult) {
+		for _, r := range page {
+			if k := key(r); k != "" {
+				counts[k]++
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GatewayOf returns the gateway path a child object belongs to, e.g.
+// "/infra/tier-1s/t1-app/nat/USER/nat-rules/r1" → "/infra/tier-1s/t1-app".
+// Returns "" when p is not under a tier-0/tier-1.
+func GatewayOf(p string) string {
+	for _, kind := range []string{"/infra/tier-1s/", "/infra/tier-0s/"} {
+		i := strings.Index(p, kind)
+		if i < 0 {
+			continue
+		}
+		rest := p[i+len(kind):]
+		if j := strings.IndexByte(rest, '/'); j >= 0 {
+			rest = rest[:j]
+		}
+		if rest == "" {
+			return ""
+		}
+		return p[:i] + kind + rest
+	}
+	return ""
+}
+
+// AggregateRequest is the body of POST /policy/api/v1/search/aggregate.
+// Primary selects the objects returned; each Related resource is joined to
+// them and counted per primary object.
+type AggregateRequest struct {
+	Primary AggregateResource   `json:"primary"`
+	Related []AggregateResource `json:"related,omitempty"`
+}
+
+// AggregateResource selects objects by resource type plus optional filters.
+type AggregateResource struct {
+	ResourceType string            `json:"resource_type"`
+	Filters      []AggregateFilter `json:"filters,omitempty"`
+	// JoinCondition links a related resource to the primary
+	// (e.g. "parent_path:path"). Ignored on Primary.
+	JoinCondition string `json:"join_condition,omitempty"`
+	// Alias names a related resource in the results. Ignored on Primary.
+	Alias string `json:"alias,omitempty"`
+}
+
+// AggregateFilter matches Value against FieldNames (Lucene value syntax).
+type AggregateFilter struct {
+	FieldNames string `json:"field_names"`
+	Value      string `json:"value"`
+}
+
+// AggregateResult is one primary object with the count of each related
+// resource joined to it.
+type AggregateResult struct {
+	Primary SearchResult       `json:"primary"`
+	Related []AggregateRelated `json:"related"`
+}
+
+// AggregateRelated is the tally of one related resource (by alias). The
+// joined objects themselves are not decoded.
+type AggregateRelated struct {
+	Alias string `json:"alias"`
+	Count int    `json:"count"`
+}
+
+// RelatedCount returns the count of the related resource named alias, or 0.
+func (r *AggregateResult) RelatedCount(alias string) int {
+	for _, rel := range r.Related {
+		if rel.Alias == alias {
+			return rel.Count
+		}
+	}
+	return 0
+}
+
+type aggregateResultList struct {
+	ResultCount int               `json:"result_count"`
+	Cursor      string            `json:"cursor"`
+	Results     []AggregateResult `json:"results"`
+}
+
+// SearchAggregate runs POST /policy/api/v1/search/aggregate and calls fn once
+// per page of primary objects. Counting children through Related replaces
+// one list call per parent (e.g. the rules of every gateway policy).
+func (c *Client) SearchAggregate(ctx context.Context, req AggregateRequest, fn func([]AggregateResult)) error {
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages == maxSearchPages {
+			return fmt.Errorf("search aggregate %s: more than %d pages, giving up", req.Primary.ResourceType, maxSearchPages)
+		}
+		path := "/policy/api/v1/search/aggregate?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page aggregateResultList
+		if err := c.doPost(ctx, path, req, &page); err != nil {
+			return fmt.Errorf("search aggregate %s: %w", req.Primary.ResourceType, err)
+		}
+		fn(page.Results)
+		if page.Cursor == "" || len(page.Results) == 0 {
+			return nil
+		}
+		cursor = page.Cursor
+	}
+}
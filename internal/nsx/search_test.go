@@ -0,0 +1,154 @@
+package nsx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGatewayOf(t *testing.T) {
+	cases := map[string]string{
+		"/infra/tier-1s/t1-app/nat/USER":                   "/infra/tier-1s/t1-app",
+		"/infra/tier-0s/vrf-a/static-routes/default":       "/infra/tier-0s/vrf-a",
+		"/orgs/default/projects/p1/infra/tier-1s/t1-p":     "/orgs/default/projects/p1/infra/tier-1s/t1-p",
+		"/infra/domains/default/gateway-policies/pol/rule": "",
+	}
+	for in, want := range cases {
+		if got := GatewayOf(in); got != want {
+			t.Errorf("GatewayOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCountByParentPaginates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != "resource_type:PolicyNatRule" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"cursor":"p2","results":[
+				{"parent_path":"/infra/tier-1s/a/nat/USER"},
+				{"parent_path":"/infra/tier-1s/a/nat/USER"},
+				{"parent_path":"/infra/tier-1s/b/nat/INTERNAL"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"parent_path":"/infra/tier-1s/b/nat/USER"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	counts, err := c.CountByParent(context.Background(), "resource_type:PolicyNatRule", func(r SearchResult) string {
+		return GatewayOf(r.ParentPath)
+	})
+	if err != nil {
+		t.Fatalf("CountByParent: %v", err)
+	}
+	if counts["/infra/tier-1s/a"] != 2 || counts["/infra/tier-1s/b"] != 2 {
+		t.Fatalf("counts = %v", counts)
+	}
+
+	// A 400 on one query says nothing about the endpoint.
+	_, err = c.CountByParent(context.Background(), "bogus", func(SearchResult) string { return "" })
+	if err == nil || IsUnsupported(err) {
+		t.Fatalf("err = %v, want a 400 that is not unsupported", err)
+	}
+}
+
+func TestSearchCountModifiedSince(t *testing.T) {
+	want := "(resource_type:Segment) AND _last_modified_time:[1700000000000 TO *]"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != want || r.URL.Query().Get("page_size") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"result_count":7,"results":[{"id":"s1"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	n, err := c.SearchCount(context.Background(), ModifiedSince("resource_type:Segment", 1700000000000))
+	if err != nil {
+		t.Fatalf("SearchCount: %v", err)
+	}
+	if n != 7 {
+		t.Fatalf("count = %d, want 7", n)
+	}
+}
+
+func TestCountDFWRuleGroupRefsStuckCursor(t *testing.T) {
+	defer func(n int) { maxSearchPages = n }(maxSearchPages)
+	maxSearchPages = 3
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"cursor":"same","results":[{"parent_path":"/infra/domains/default/security-policies/app",
+			"source_groups":["/infra/domains/default/groups/g"]}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.CountDFWRuleGroupRefs(context.Background()); err == nil {
+		t.Fatalf("CountDFWRuleGroupRefs returned no error on a cursor that never ends")
+	}
+	if calls != maxSearchPages {
+		t.Errorf("calls = %d, want %d", calls, maxSearchPages)
+	}
+}
+
+func TestSearchAggregatePaginates(t *testing.T) {
+	var bodies []AggregateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/policy/api/v1/search/aggregate" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req AggregateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodies = append(bodies, req)
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"cursor":"p2","results":[{"primary":{"path":"/p1"},"related":[{"alias":"rules","count":3}]}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"primary":{"path":"/p2"},"related":[{"alias":"other","count":5}]}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	req := AggregateRequest{
+		Primary: AggregateResource{ResourceType: "GatewayPolicy"},
+		Related: []AggregateResource{{ResourceType: "Rule", JoinCondition: "parent_path:path", Alias: "rules"}},
+	}
+	rules := map[string]int{}
+	err = c.SearchAggregate(context.Background(), req, func(page []AggregateResult) {
+		for i := range page {
+			rules[page[i].Primary.Path] = page[i].RelatedCount("rules")
+		}
+	})
+	if err != nil {
+		t.Fatalf("SearchAggregate: %v", err)
+	}
+	if len(rules) != 2 || rules["/p1"] != 3 || rules["/p2"] != 0 {
+		t.Fatalf("rules = %v", rules)
+	}
+	if len(bodies) != 2 || bodies[1].Related[0].Alias != "rules" {
+		t.Fatalf("request bodies = %+v, want the same join on every page", bodies)
+	}
+}
@@ -0,0 +1,158 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// session holds the JSESSIONID cookie and X-XSRF-TOKEN obtained from
+// POST /api/session/create. gen is bumped on every successful login so that
+// concurrent requests failing with 401 on the same (stale) session trigger a
+// single re-login instead of one per goroutine. base is the endpoint that
+// issued the session; after a failover the next request logs in again.
+type session struct {
+	mu     sync.Mutex
+	cookie *http.Cookie
+	xsrf   string
+	gen    uint64
+	base   string
+}
+
+// authorize attaches credentials to req (built against base) according to
+// the client's auth mode. In session mode it logs in first when there is no
+// live session for base. The returned generation identifies the session
+// used, for invalidateSession.
+func (c *Client) authorize(ctx context.Context, req *http.Request, base string) (uint64, error) {
+	if c.authMode != AuthSession {
+		req.SetBasicAuth(c.username, c.password)
+		return 0, nil
+	}
+
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	if c.session.cookie == nil || c.session.base != base {
+		if err := c.login(ctx, base); err != nil {
+			return 0, err
+		}
+	}
+	req.AddCookie(c.session.cookie)
+	req.Header.Set("X-XSRF-TOKEN", c.session.xsrf)
+	return c.session.gen, nil
+}
+
+// login performs POST /api/session/create against base. Caller must hold
+// c.session.mu.
+func (c *Client) login(ctx context.Context, base string) error {
+	cookie, xsrf, err := c.createSession(ctx, base)
+	if err != nil {
+		return err
+	}
+	c.session.cookie = cookie
+	c.session.xsrf = xsrf
+	c.session.base = base
+	c.session.gen++
+	return nil
+}
+
+// createSession logs in to base and returns the session cookie and XSRF
+// token, without touching c.session.
+func (c *Client) createSession(ctx context.Context, base string) (*http.Cookie, string, error) {
+	form := url.Values{}
+	form.Set("j_username", c.username)
+	form.Set("j_password", c.password)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/session/create", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, "", fmt.Errorf("creating session request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("session create: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp, "/api/session/create")
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("session create: %w", apiErr)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	var cookie *http.Cookie
+	for _, ck := range resp.Cookies() {
+		if ck.Name == "JSESSIONID" {
+			cookie = ck
+			break
+		}
+	}
+	xsrf := resp.Header.Get("X-XSRF-TOKEN")
+	if cookie == nil || xsrf == "" {
+		return nil, "", fmt.Errorf("session create: response missing JSESSIONID or X-XSRF-TOKEN")
+	}
+	return &http.Cookie{Name: cookie.Name, Value: cookie.Value}, xsrf, nil
+}
+
+// adoptSession installs a session created against base by a failover probe,
+// so the first request after the switch does not log in a second time.
+func (c *Client) adoptSession(base string, cookie *http.Cookie, xsrf string) {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	c.session.cookie = cookie
+	c.session.xsrf = xsrf
+	c.session.base = base
+	c.session.gen++
+}
+
+// invalidateSession drops the current session if it is still the one that
+// was rejected (gen). A newer session created by another goroutine is kept.
+func (c *Client) invalidateSession(gen uint64) {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	if c.session.gen == gen {
+		c.session.cookie = nil
+		c.session.xsrf = ""
+	}
+}
+
+// Close logs out of the Manager (POST /api/session/destroy) when running in
+// session mode so the session does not linger until its idle timeout.
+// It is a no-op in basic mode or when no session was ever opened.
+func (c *Client) Close(ctx context.Context) error {
+	if c.authMode != AuthSession {
+		return nil
+	}
+
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	if c.session.cookie == nil {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.session.base+"/api/session/destroy", nil)
+	if err != nil {
+		return fmt.Errorf("creating logout request: %w", err)
+	}
+	req.AddCookie(c.session.cookie)
+	req.Header.Set("X-XSRF-TOKEN", c.session.xsrf)
+	c.session.cookie = nil
+	c.session.xsrf = ""
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("session destroy: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp, "/api/session/destroy")
+		resp.Body.Close()
+		return fmt.Errorf("session destroy: %w", apiErr)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
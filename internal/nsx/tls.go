@@ -0,0 +1,171 @@
+package nsx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"nsx-collector/internal/telemetry"
+)
+
+// TLSOptions controls how the client verifies the Manager certificate and
+// whether it presents a client certificate (principal identity auth).
+type TLSOptions struct {
+	// SkipVerify disables all verification (tls_skip_verify). Legacy.
+	SkipVerify bool
+	// CAFile is a PEM bundle used instead of the system roots.
+	CAFile string
+	// Fingerprints pins the Manager leaf certificate by SHA-256 (hex, colons
+	// optional). Without CAFile the chain is not checked — the pin is the
+	// trust anchor, which is what self-signed NSX certificates need. With
+	// CAFile both must pass.
+	Fingerprints []string
+	// ClientCertFile / ClientKeyFile are a PEM certificate + key presented
+	// to the Manager (principal identity). Both or neither.
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// TLSVerifyError is returned when the Manager certificate fails
+// verification (unknown CA, hostname mismatch, expired, pin mismatch).
+// Startup treats it as fatal: retrying will not fix it.
+type TLSVerifyError struct {
+	Host string
+	Err  error
+}
+
+func (e *TLSVerifyError) Error() string {
+	return fmt.Sprintf("tls verification failed for %s: %v (check ca_file / cert_fingerprints in managers.yaml)", e.Host, e.Err)
+}
+
+func (e *TLSVerifyError) Unwrap() error { return e.Err }
+
+// IsTLSVerify reports whether err is a Manager certificate verification failure.
+func IsTLSVerify(err error) bool {
+	var tv *TLSVerifyError
+	if errors.As(err, &tv) {
+		return true
+	}
+	var certErr *tls.CertificateVerificationError
+	var unknownCA x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	return errors.As(err, &certErr) || errors.As(err, &unknownCA) ||
+		errors.As(err, &hostErr) || errors.As(err, &invalid)
+}
+
+// errPinMismatch is the cause wrapped in TLSVerifyError when no pin matches.
+var errPinMismatch = errors.New("certificate fingerprint does not match cert_fingerprints")
+
+// buildTLSConfig turns TLSOptions into a tls.Config. site labels the
+// certificate-expiry gauge, which is refreshed on every handshake.
+func buildTLSConfig(site string, opts TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: opts.SkipVerify} //nolint:gosec
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s: no PEM certificates found", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	var pins [][]byte
+	for _, fp := range opts.Fingerprints {
+		raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fp), ":", ""))
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("cert_fingerprints: %q is not a SHA-256 hex digest", fp)
+		}
+		pins = append(pins, raw)
+	}
+	if len(pins) > 0 && opts.CAFile == "" {
+		// The pin replaces chain verification (self-signed Manager certs).
+		cfg.InsecureSkipVerify = true //nolint:gosec
+	}
+
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return nil
+		}
+		leaf := cs.PeerCertificates[0]
+		telemetry.NSXCertExpiryDays.WithLabelValues(site, cs.ServerName).
+			Set(time.Until(leaf.NotAfter).Hours() / 24)
+		if len(pins) == 0 {
+			return nil
+		}
+		sum := sha256.Sum256(leaf.Raw)
+		for _, pin := range pins {
+			if bytes.Equal(sum[:], pin) {
+				return nil
+			}
+		}
+		return &TLSVerifyError{Host: cs.ServerName, Err: fmt.Errorf("%w (got %s)", errPinMismatch, formatFingerprint(sum[:]))}
+	}
+	return cfg, nil
+}
+
+// formatFingerprint renders a digest as AA:BB:... — the form the NSX UI and
+// `openssl x509 -fingerprint -sha256` show, so it can be pasted into config.
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// CheckTLS performs one TLS handshake against the Manager so misconfigured
+// certificates surface at startup instead of as per-cycle collect errors.
+// Verification failures are returned as *TLSVerifyError; network errors are
+// returned as-is (the Manager may just be down). No-op for http:// URLs.
+func (c *Client) CheckTLS(ctx context.Context) error {
+	u, err := url.Parse(c.baseURL())
+	if err != nil {
+		return fmt.Errorf("parsing manager url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "443")
+	}
+	cfg := c.tls.Clone()
+	cfg.ServerName = u.Hostname()
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: 10 * time.Second}, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		if IsTLSVerify(err) {
+			var tv *TLSVerifyError
+			if errors.As(err, &tv) {
+				return tv
+			}
+			return &TLSVerifyError{Host: u.Hostname(), Err: err}
+		}
+		return fmt.Errorf("tls check %s: %w", addr, err)
+	}
+	return conn.Close()
+}
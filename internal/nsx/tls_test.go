@@ -0,0 +1,58 @@
+package nsx
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCertificatePinning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cluster_id":"c1"}`))
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	ctx := context.Background()
+
+	// Unverified self-signed cert: startup check must flag it.
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.CheckTLS(ctx); !IsTLSVerify(err) {
+		t.Fatalf("CheckTLS without pin: err = %v, want TLS verify error", err)
+	}
+
+	// Pinned (colon form, lower case): accepted without a CA.
+	pin := strings.ToLower(formatFingerprint(sum[:]))
+	c, err = NewClient(Options{BaseURL: srv.URL, TLS: TLSOptions{Fingerprints: []string{pin}}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.CheckTLS(ctx); err != nil {
+		t.Fatalf("CheckTLS with pin: %v", err)
+	}
+	if _, err := c.GetClusterStatus(ctx); err != nil {
+		t.Fatalf("GetClusterStatus with pin: %v", err)
+	}
+
+	// Wrong pin: rejected, and not retried as a transient failure.
+	wrong := strings.Repeat("00", sha256.Size)
+	c, err = NewClient(Options{BaseURL: srv.URL, TLS: TLSOptions{Fingerprints: []string{wrong}}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.CheckTLS(ctx); !IsTLSVerify(err) {
+		t.Fatalf("CheckTLS with wrong pin: err = %v, want TLS verify error", err)
+	}
+	if _, err := c.GetClusterStatus(ctx); !IsTLSVerify(err) {
+		t.Fatalf("GetClusterStatus with wrong pin: err = %v, want TLS verify error", err)
+	}
+
+	if _, err := NewClient(Options{TLS: TLSOptions{Fingerprints: []string{"abc"}}}); err == nil {
+		t.Fatalf("NewClient accepted a malformed fingerprint")
+	}
+}
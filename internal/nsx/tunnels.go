@@ -0,0 +1,62 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Transport node tunnels
+// GET /api/v1/transport-nodes/<id>/tunnels
+// ---------------------------------------------------------------------------
+
+// TunnelList represents one page of a transport node's tunnels. Unlike most
+// lists the entries are under "tunnels", not "results".
+type TunnelList struct {
+	ResultCount int      `json:"result_count"`
+	Cursor      string   `json:"cursor"`
+	Tunnels     []Tunnel `json:"tunnels"`
+}
+
+// Tunnel is one overlay tunnel from a transport node to a remote TEP.
+type Tunnel struct {
+	Name                  string `json:"name"`
+	Status                string `json:"status"` // UP | DOWN | DEGRADED | UNKNOWN
+	LocalIP               string `json:"local_ip"`
+	RemoteIP              string `json:"remote_ip"`
+	RemoteNodeID          string `json:"remote_node_id"`
+	RemoteNodeDisplayName string `json:"remote_node_display_name"`
+	Encap                 string `json:"encap"` // GENEVE | VXLAN | STT
+	EgressInterface       string `json:"egress_interface"`
+	BFD                   struct {
+		State      string `json:"state"` // UP | DOWN | INIT | ADMIN_DOWN
+		Diagnostic string `json:"diagnostic"`
+	} `json:"bfd"`
+}
+
+// Up reports whether the tunnel is UP.
+func (t *Tunnel) Up() bool { return strings.EqualFold(t.Status, "UP") }
+
+// GetTransportNodeTunnels returns every tunnel of a transport node.
+func (c *Client) GetTransportNodeTunnels(ctx context.Context, nodeID string) ([]Tunnel, error) {
+	var all []Tunnel
+	cursor := ""
+	for {
+		path := "/api/v1/transport-nodes/" + nodeID + "/tunnels?page_size=1000"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		var page TunnelList
+		if err := c.doGet(ctx, path, &page); err != nil {
+			return nil, fmt.Errorf("transport node %s tunnels: %w", nodeID, err)
+		}
+		all = append(all, page.Tunnels...)
+		if page.Cursor == "" || len(page.Tunnels) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return all, nil
+}
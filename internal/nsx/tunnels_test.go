@@ -0,0 +1,45 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTransportNodeTunnels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/transport-nodes/tn-1/tunnels" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"cursor":"p2","result_count":2,"tunnels":[
+				{"name":"geneve1","status":"UP","local_ip":"10.0.0.1","remote_ip":"10.0.0.2",
+				 "remote_node_id":"tn-2","encap":"GENEVE","bfd":{"state":"UP","diagnostic":"No Diagnostic"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"result_count":2,"tunnels":[
+			{"name":"geneve2","status":"DOWN","remote_node_id":"tn-3",
+			 "bfd":{"state":"DOWN","diagnostic":"Control Detection Time Expired"}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	tunnels, err := c.GetTransportNodeTunnels(context.Background(), "tn-1")
+	if err != nil {
+		t.Fatalf("GetTransportNodeTunnels: %v", err)
+	}
+	if len(tunnels) != 2 {
+		t.Fatalf("got %d tunnels, want 2", len(tunnels))
+	}
+	if !tunnels[0].Up() || tunnels[0].Encap != "GENEVE" || tunnels[0].BFD.State != "UP" {
+		t.Errorf("tunnels[0] = %+v", tunnels[0])
+	}
+	if tunnels[1].Up() || tunnels[1].BFD.Diagnostic != "Control Detection Time Expired" {
+		t.Errorf("tunnels[1] = %+v", tunnels[1])
+	}
+}
@@ -0,0 +1,249 @@
+package nsx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NodeVersion represents GET /api/v1/node/version.
+type NodeVersion struct {
+	NodeVersion    string `json:"node_version"`    // e.g. 3.2.3.1.0.22104592
+	ProductVersion string `json:"product_version"` // e.g. 3.2.3.1.0.22104592
+}
+
+// Version is the major.minor.patch part of an NSX version string. The zero
+// value means "unknown".
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion reads the leading major.minor[.patch] of s; build suffixes
+// are ignored. Returns the zero Version when s is unparseable.
+func ParseVersion(s string) Version {
+	parts := strings.SplitN(strings.TrimSpace(s), ".", 4)
+	var nums [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			if i < 2 {
+				return Version{}
+			}
+			break
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}
+}
+
+// BuildNumber returns the build of an NSX version string: its last
+// all-digit token of 5+ digits, so "3.2.3.1.0.22104592" gives "22104592"
+// and the host VIB form "3.2.3.1.0-7.0.22104638" gives "22104638". Host VIBs
+// are built apart from the Manager, so builds only compare within a node
+// type. "" when none.
+func BuildNumber(s string) string {
+	tokens := strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '-' || r == ' ' })
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if len(tokens[i]) < 5 {
+			continue
+		}
+		if _, err := strconv.ParseUint(tokens[i], 10, 64); err == nil {
+			return tokens[i]
+		}
+	}
+	return ""
+}
+
+// Release returns the release of an NSX version string as
+// major.minor.patch.update.hotfix, missing parts as 0: "4.1.2.1.0.22667789"
+// (Manager, edge) and "4.1.2.1.0-8.0.22667792" (host VIB, ESXi 8.0) both
+// give "4.1.2.1.0", and "4.1.2" gives "4.1.2.0.0". It stops at the host
+// suffix and at the first 5+ digit token (the build). "" when s does not
+// start with major.minor.
+func Release(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "-")
+	parts := make([]string, 0, 5)
+	for _, tok := range strings.Split(s, ".") {
+		if len(parts) == 5 || len(tok) >= 5 {
+			break
+		}
+		if _, err := strconv.ParseUint(tok, 10, 32); err != nil {
+			break
+		}
+		parts = append(parts, tok)
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	for len(parts) < 5 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".")
+}
+
+// SameRelease reports whether two NSX version strings name the same
+// release, comparing Release when both parse and the strings otherwise.
+// Build numbers are left out: host VIBs carry their own.
+func SameRelease(a, b string) bool {
+	ra, rb := Release(a), Release(b)
+	if ra != "" && rb != "" {
+		return ra == rb
+	}
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+// Known reports whether v was parsed from a Manager answer.
+func (v Version) Known() bool { return v != Version{} }
+
+// AtLeast reports whether v >= o.
+func (v Version) AtLeast(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major > o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor > o.Minor
+	}
+	return v.Patch >= o.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capability names an optional Manager API the collectors may use.
+type Capability string
+
+const (
+	// CapPolicySearch is GET /policy/api/v1/search/query.
+	CapPolicySearch Capability = "policy_search"
+	// CapSearchAggregate is POST /policy/api/v1/search/aggregate.
+	CapSearchAggregate Capability = "search_aggregate"
+	// CapLBNodeUsageSummary is /policy/api/v1/infra/lb-node-usage-summary.
+	CapLBNodeUsageSummary Capability = "lb_node_usage_summary"
+)
+
+// minVersions is the first NSX release that ships each capability. Anything
+// not listed is assumed available everywhere.
+var minVersions = map[Capability]Version{
+	CapPolicySearch:       {Major: 3, Minor: 0},
+	CapSearchAggregate:    {Major: 3, Minor: 1},
+	CapLBNodeUsageSummary: {Major: 3, Minor: 0},
+}
+
+// unsupportedTTL is how long a runtime mark lasts: long enough to stop a
+// missing endpoint failing every cycle, short enough that a mark set by a
+// misread answer does not outlive the day.
+const unsupportedTTL = time.Hour
+
+// capabilities is the per-client registry: the Manager version plus the
+// capabilities learnt unsupported at runtime (an endpoint that answered
+// 404/501). Runtime marks expire after unsupportedTTL and are cleared when
+// the version changes, so an upgrade re-enables them at once.
+type capabilities struct {
+	mu          sync.Mutex
+	version     Version
+	raw         string // full product version, with build
+	unsupported map[Capability]unsupportedMark
+}
+
+// unsupportedMark is one runtime mark.
+type unsupportedMark struct {
+	reason string
+	until  time.Time
+}
+
+// RefreshVersion reads /api/v1/node/version and updates the registry.
+// Called at startup and on the slow path.
+func (c *Client) RefreshVersion(ctx context.Context) (*NodeVersion, error) {
+	var nv NodeVersion
+	if err := c.doGet(ctx, "/api/v1/node/version", &nv); err != nil {
+		return nil, fmt.Errorf("node version: %w", err)
+	}
+	raw := nv.ProductVersion
+	if raw == "" {
+		raw = nv.NodeVersion
+	}
+	v := ParseVersion(raw)
+
+	c.caps.mu.Lock()
+	if v != c.caps.version {
+		c.caps.unsupported = nil
+	}
+	c.caps.version = v
+	c.caps.raw = raw
+	c.caps.mu.Unlock()
+	return &nv, nil
+}
+
+// Version returns the last Manager version read by RefreshVersion (zero
+// when never read).
+func (c *Client) Version() Version {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	return c.caps.version
+}
+
+// ProductVersion returns the full Manager version string last read by
+// RefreshVersion, build included ("" when never read).
+func (c *Client) ProductVersion() string {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	return c.caps.raw
+}
+
+// Supports reports whether the Manager is expected to implement cp: not
+// marked unsupported at runtime and, when the version is known, at least the
+// capability's minimum release. Unknown versions are treated optimistically —
+// the first failing call then marks the capability.
+func (c *Client) Supports(cp Capability) bool {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	if m, off := c.caps.unsupported[cp]; off {
+		if time.Now().Before(m.until) {
+			return false
+		}
+		delete(c.caps.unsupported, cp)
+	}
+	minV, ok := minVersions[cp]
+	if !ok || !c.caps.version.Known() {
+		return true
+	}
+	return c.caps.version.AtLeast(minV)
+}
+
+// MarkUnsupported disables cp for unsupportedTTL, or until the Manager
+// version changes. reason is kept for Unsupported. Only call it when the
+// capability's own endpoint answered IsUnsupported.
+func (c *Client) MarkUnsupported(cp Capability, reason string) {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	if c.caps.unsupported == nil {
+		c.caps.unsupported = map[Capability]unsupportedMark{}
+	}
+	c.caps.unsupported[cp] = unsupportedMark{reason: reason, until: time.Now().Add(unsupportedTTL)}
+}
+
+// Unsupported lists the capabilities currently off, with the reason: either
+// the runtime mark or the minimum version the Manager is below.
+func (c *Client) Unsupported() map[Capability]string {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	out := map[Capability]string{}
+	now := time.Now()
+	for cp, m := range c.caps.unsupported {
+		if now.Before(m.until) {
+			out[cp] = m.reason
+		}
+	}
+	if c.caps.version.Known() {
+		for cp, minV := range minVersions {
+			if _, ok := out[cp]; !ok && !c.caps.version.AtLeast(minV) {
+				out[cp] = "requires NSX " + minV.String()
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package nsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]Version{
+		"3.2.3.1.0.22104592": {3, 2, 3},
+		"4.1.0.0.0.21332672": {4, 1, 0},
+		"3.1":                {3, 1, 0},
+		"garbage":            {},
+	}
+	for in, want := range cases {
+		if got := ParseVersion(in); got != want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestSameRelease(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"4.1.2.1.0.22667789", "4.1.2.1.0.22667789", true},
+		// Host VIB software_version: own build, ESXi release after the dash.
+		{"4.1.2.1.0.22667789", "4.1.2.1.0-8.0.22667792", true},
+		{"3.2.3.1.0.22104592", "3.2.3.1.0-7.0.22104638", true},
+		{"4.1.2.1.0.22667789", "4.1.2.0.0-8.0.22411149", false},
+		{"4.1.2.1.0.22667789", "4.1.1.0.0.22224317", false},
+		{"4.1.2", "4.1.2.0.0.22589037", true},
+		{"3.2.3", "3.2.4", false},
+		{"unknown", "unknown", true},
+	}
+	for _, tc := range cases {
+		if got := SameRelease(tc.a, tc.b); got != tc.want {
+			t.Errorf("SameRelease(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+	if got := BuildNumber("4.1.2.1.0.22667789"); got != "22667789" {
+		t.Errorf("BuildNumber = %q", got)
+	}
+	if got := Release("4.1.2.1.0-8.0.22667792"); got != "4.1.2.1.0" {
+		t.Errorf("Release = %q", got)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	version := `{"product_version":"3.0.2.0.0.1"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(version))
+	}))
+	defer srv.Close()
+	c, err := NewClient(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	if !c.Supports(CapSearchAggregate) {
+		t.Fatalf("unknown version must be optimistic")
+	}
+	if _, err := c.RefreshVersion(ctx); err != nil {
+		t.Fatalf("RefreshVersion: %v", err)
+	}
+	if c.Supports(CapSearchAggregate) {
+		t.Fatalf("search aggregate must be off on 3.0")
+	}
+	if !c.Supports(CapPolicySearch) {
+		t.Fatalf("policy search must be on on 3.0")
+	}
+
+	c.MarkUnsupported(CapPolicySearch, "404")
+	if c.Supports(CapPolicySearch) {
+		t.Fatalf("MarkUnsupported ignored")
+	}
+	if _, err := c.RefreshVersion(ctx); err != nil {
+		t.Fatalf("RefreshVersion: %v", err)
+	}
+	if c.Supports(CapPolicySearch) {
+		t.Fatalf("runtime mark must survive a refresh with the same version")
+	}
+	c.caps.mu.Lock()
+	m := c.caps.unsupported[CapPolicySearch]
+	m.until = time.Now().Add(-time.Second)
+	c.caps.unsupported[CapPolicySearch] = m
+	c.caps.mu.Unlock()
+	if !c.Supports(CapPolicySearch) {
+		t.Fatalf("expired runtime mark still applies: %v", c.Unsupported())
+	}
+
+	c.MarkUnsupported(CapPolicySearch, "404")
+
+	version = `{"product_version":"3.2.1.0.0.1"}`
+	if _, err := c.RefreshVersion(ctx); err != nil {
+		t.Fatalf("RefreshVersion: %v", err)
+	}
+	if !c.Supports(CapPolicySearch) || !c.Supports(CapSearchAggregate) {
+		t.Fatalf("upgrade must clear runtime marks: %v", c.Unsupported())
+	}
+}
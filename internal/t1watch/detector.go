@@ -7,7 +7,8 @@ import (
 // LiveT1 describes one T1 as observed in the current cycle, already enriched
 // with parent T0/VRF and edge cluster identity by the worker.
 type LiveT1 struct {
-	ID              string
+	ID              string // unique_id (legacy logical-router UUID)
+	PolicyID        string // Policy API id, for GET /policy/api/v1/infra/tier-1s/<id>
 	Name            string
 	ParentT0ID      string
 	ParentT0Name    string
@@ -78,6 +79,7 @@ func Detect(
 		// New to us: add to snapshot.
 		snapshot.Known[id] = T1Info{
 			ID:              t.ID,
+			PolicyID:        t.PolicyID,
 			Name:            t.Name,
 			ParentT0ID:      t.ParentT0ID,
 			ParentT0Name:    t.ParentT0Name,
@@ -113,6 +115,7 @@ func Detect(
 			Kind: "deleted",
 			T1: LiveT1{
 				ID:              info.ID,
+				PolicyID:        info.PolicyID,
 				Name:            info.Name,
 				ParentT0ID:      info.ParentT0ID,
 				ParentT0Name:    info.ParentT0Name,
@@ -137,7 +140,9 @@ func Detect(
 		if live.EdgeClusterID != info.EdgeClusterID ||
 			live.EdgeClusterName != info.EdgeClusterName ||
 			live.ParentT0Name != info.ParentT0Name ||
-			live.Name != info.Name {
+			live.Name != info.Name ||
+			live.PolicyID != info.PolicyID {
+			info.PolicyID = live.PolicyID
 			info.Name = live.Name
 			info.ParentT0ID = live.ParentT0ID
 			info.ParentT0Name = live.ParentT0Name
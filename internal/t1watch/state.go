@@ -17,6 +17,7 @@ import (
 // T1Info is one entry in the persisted snapshot.
 type T1Info struct {
 	ID              string `json:"id"`
+	PolicyID        string `json:"policy_id,omitempty"` // empty in snapshots written before it was kept
 	Name            string `json:"name"`
 	ParentT0ID      string `json:"parent_t0_id"`
 	ParentT0Name    string `json:"parent_t0_name"`
@@ -48,6 +48,79 @@ var (
 		Help: "Times a missing T1 in the HA-watch inventory was replaced by a fresh pick.",
 	}, []string{"site", "t0_cluster"})
 
+	// Cluster group health
+	ClusterGroupEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_cluster_group_events_total",
+		Help: "Cluster group status transitions detected (event=degraded|unavailable|recovered).",
+	}, []string{"site", "group_type", "event"})
+
+	// BGP neighbor collection telemetry
+	BGPPolls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_bgp_polls_total",
+		Help: "Total BGP neighbor collection cycles (one per gated tick of intervals.bgp).",
+	}, []string{"site"})
+
+	BGPSessionsDown = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_bgp_sessions_down",
+		Help: "BGP sessions (neighbor x edge) not ESTABLISHED in the last poll.",
+	}, []string{"site"})
+
+	BGPEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_bgp_events_total",
+		Help: "BGP session transitions detected (event=down|up).",
+	}, []string{"site", "event"})
+
+	// Per-T1 traffic sampling
+	T1TrafficSampled = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_t1_traffic_sampled",
+		Help: "T1 uplink ports polled in the last t1_traffic run (bounded by mode/top_n/sample_size).",
+	}, []string{"site"})
+
+	// Certificate inventory
+	CertificatesExpiring = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_certificates_expiring",
+		Help: "Certificates within the largest certificates.notify_days threshold (or already expired).",
+	}, []string{"site"})
+
+	CertificateNotices = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_certificate_notices_total",
+		Help: "Certificate expiry notices posted to Slack.",
+	}, []string{"site"})
+
+	// Manager backups
+	BackupNotices = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_backup_notices_total",
+		Help: "Stale/fresh backup notices posted to Slack.",
+	}, []string{"site"})
+
+	// Tunnel inventory
+	TunnelsDown = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_tunnels_down",
+		Help: "Tunnels not UP across the transport nodes read in the last tunnels run.",
+	}, []string{"site"})
+
+	TunnelEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_tunnel_events_total",
+		Help: "Tunnel state changes detected (event=down|up).",
+	}, []string{"site", "event"})
+
+	// Realization state
+	RealizationErrors = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_realization_errors",
+		Help: "Objects in realization ERROR as of the last realization run.",
+	}, []string{"site", "object_type"})
+
+	RealizationEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_realization_events_total",
+		Help: "Objects entering or leaving realization ERROR (event=failed|recovered).",
+	}, []string{"site", "object_type", "event"})
+
+	// Gateway firewall rule statistics
+	FWUnusedRules = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_fw_unused_rules",
+		Help: "Gateway firewall rules without hits for fw_rule_stats.unused_days in the last run.",
+	}, []string{"site"})
+
 	// T1Watch — new/deleted Tier-1 detection + Slack notifications
 	T1Created = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "nsx_collector_t1_created_total",
@@ -84,4 +157,56 @@ var (
 		Name: "nsx_collector_capacity_extras_polls_total",
 		Help: "Total invocations of extended capacity collections (segments/NAT/FW/groups).",
 	}, []string{"site", "kind"})
+
+	// NSX API request budget — shared adaptive limiter per manager
+	NSXRequestBudget = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_nsx_request_budget",
+		Help: "Current NSX API request budget (requests/sec) after adaptive backoff.",
+	}, []string{"site"})
+
+	NSXRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_nsx_requests_in_flight",
+		Help: "NSX API requests currently in flight.",
+	}, []string{"site"})
+
+	NSXQueueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nsx_collector_nsx_queue_wait_seconds",
+		Help:    "Time NSX API requests spent waiting for the shared limiter.",
+		Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"site"})
+
+	NSXThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_nsx_throttled_total",
+		Help: "Total HTTP 429 responses received from the NSX Manager.",
+	}, []string{"site"})
+
+	NSXRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_nsx_retries_total",
+		Help: "Total NSX API requests retried after a transient failure, by normalized endpoint.",
+	}, []string{"site", "endpoint"})
+
+	NSXFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_nsx_failovers_total",
+		Help: "Total switches between the Manager VIP and individual Manager nodes (reason: failover|failback).",
+	}, []string{"site", "reason"})
+
+	NSXCertExpiryDays = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_nsx_cert_expiry_days",
+		Help: "Days until the NSX Manager TLS certificate expires, refreshed on each handshake.",
+	}, []string{"site", "host"})
+
+	NSXVersionInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_nsx_version_info",
+		Help: "NSX Manager product version per site (value is always 1).",
+	}, []string{"site", "version"})
+
+	NSXVersionDrift = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nsx_collector_nsx_version_drift_nodes",
+		Help: "Nodes running a different NSX release than the Manager (node_type=manager|edge|host).",
+	}, []string{"site", "node_type"})
+
+	NSXCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nsx_collector_nsx_cache_requests_total",
+		Help: "Inventory cache lookups by resource (result: hit|miss|shared — shared joined an in-flight fetch).",
+	}, []string{"site", "resource", "result"})
 )